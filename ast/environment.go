@@ -7,11 +7,33 @@ import (
 )
 
 var TablesFieldsNames = map[string][]string{
-	"refs":     {"name", "full_name", "type", "repo"},
-	"commits":  {"change_id", "commit_id", "title", "message", "name", "email", "datetime", "repo"},
-	"branches": {"name", "commit_count", "is_head", "is_remote", "repo"},
-	"diffs":    {"change_id", "commit_id", "name", "email", "insertions", "deletions", "files_changed", "repo"},
-	"tags":     {"name", "repo"},
+	"refs":             {"name", "full_name", "type", "repo"},
+	"commits":          {"change_id", "commit_id", "title", "message", "name", "email", "datetime", "committer_name", "committer_email", "commit_datetime", "parent_ids", "parent_count", "is_merge", "tree_id", "signature", "is_signed", "repo"},
+	"branches":         {"name", "commit_count", "is_head", "is_remote", "upstream", "ahead", "behind", "repo"},
+	"remotes":          {"name", "url", "fetch_refspecs", "push_url", "repo"},
+	"diffs":            {"change_id", "commit_id", "name", "email", "insertions", "deletions", "files_changed", "repo"},
+	"tags":             {"name", "commit_id", "tag_object_id", "is_annotated", "tagger_name", "tagger_email", "datetime", "message", "repo"},
+	"files":            {"path", "name", "extension", "size", "mode", "blob_id", "is_binary", "revision", "repo"},
+	"blame":            {"path", "line_no", "line", "commit_id", "name", "email", "datetime", "revision", "repo"},
+	"diff_files":       {"commit_id", "path", "old_path", "change_type", "insertions", "deletions", "is_binary", "repo"},
+	"diff_hunks":       {"commit_id", "path", "hunk_index", "old_start", "old_lines", "new_start", "new_lines", "kind", "content", "repo"},
+	"reflog":           {"ref", "index", "old_id", "new_id", "name", "email", "datetime", "message", "repo"},
+	"stashes":          {"index", "commit_id", "message", "branch", "datetime", "files_changed", "repo"},
+	"status":           {"path", "staging", "worktree", "is_untracked", "repo"},
+	"submodules":       {"name", "path", "url", "branch", "commit_id", "is_initialized", "revision", "repo"},
+	"notes":            {"notes_ref", "commit_id", "note", "repo"},
+	"gerrit_changes":   {"change_number", "change_id", "patchset_count", "current_patchset", "commit_id", "uploader_name", "uploader_email", "datetime", "repo"},
+	"gerrit_patchsets": {"change_number", "patchset_number", "commit_id", "change_id", "uploader_name", "uploader_email", "datetime", "repo"},
+	"gerrit_votes":     {"change_number", "patchset_number", "label", "value", "voter", "datetime", "repo"},
+	"gerrit_meta":      {"change_number", "status", "topic", "hashtags", "submitter", "repo"},
+	"commit_trailers":  {"commit_id", "key", "value", "position", "repo"},
+}
+
+var TablesMutableFieldsNames = map[string][]string{
+	"refs":     {"full_name", "commit_id"},
+	"branches": {"name", "commit_id"},
+	"tags":     {"name", "commit_id", "message"},
+	"notes":    {"notes_ref", "commit_id", "note"},
 }
 
 type Environment struct {
@@ -54,6 +76,16 @@ func (e *Environment) ResolveType(str string) (DataType, error) {
 	}
 }
 
+// NewScope returns an environment sharing the global variables with an empty fields scope, subqueries are parsed and
+// evaluated in their own scope so their fields and aliases don't conflict with the outer query
+func (e *Environment) NewScope() *Environment {
+	return &Environment{
+		Globals:      e.Globals,
+		GlobalsTypes: e.GlobalsTypes,
+		Scopes:       make(map[string]DataType),
+	}
+}
+
 func (e *Environment) ClearSession() {
 	e.Scopes = make(map[string]DataType)
 }
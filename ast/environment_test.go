@@ -65,6 +65,24 @@ func TestResolveType(t *testing.T) {
 	assert.NotEqual(t, nil, err)
 }
 
+func TestNewScope(t *testing.T) {
+	env := Environment{
+		Globals:      map[string]Value{},
+		GlobalsTypes: map[string]DataType{},
+		Scopes:       map[string]DataType{},
+	}
+
+	env.Define("field1", Text{})
+	env.DefineGlobal("@field2", Integer{})
+
+	scope := env.NewScope()
+	scope.Define("field3", Boolean{})
+
+	assert.Equal(t, false, scope.Contains("field1"))
+	assert.Equal(t, true, scope.Contains("@field2"))
+	assert.Equal(t, false, env.Contains("field3"))
+}
+
 func TestClearSession(t *testing.T) {
 	env := Environment{
 		Globals:      map[string]Value{},
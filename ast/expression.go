@@ -22,6 +22,7 @@ const (
 	ExprIn
 	ExprIsNull
 	ExprNull
+	ExprSubquery
 )
 
 type Expression interface {
@@ -473,6 +474,7 @@ func (e *CaseExpression) IsConst() bool {
 type InExpression struct {
 	Argument      Expression
 	Values        []Expression
+	Subquery      *SubqueryExpression
 	ValuesType    DataType
 	HasNotKeyword bool
 }
@@ -482,7 +484,7 @@ func (e *InExpression) Kind() ExpressionKind {
 }
 
 func (e *InExpression) ExprType(scope *Environment) DataType {
-	return e.ValuesType
+	return Boolean{}
 }
 
 func (e *InExpression) AsAny() interface{} {
@@ -531,3 +533,30 @@ func (e *NullExpression) AsAny() interface{} {
 func (e *NullExpression) IsConst() bool {
 	return false
 }
+
+// SubqueryExpression is a query selecting one value, the engine evaluates it once before the outer query
+// and keeps the selected values
+type SubqueryExpression struct {
+	Query       *GQLQuery
+	Env         *Environment
+	ValueType   DataType
+	IsScalar    bool
+	Values      []Value
+	IsEvaluated bool
+}
+
+func (e *SubqueryExpression) Kind() ExpressionKind {
+	return ExprSubquery
+}
+
+func (e *SubqueryExpression) ExprType(scope *Environment) DataType {
+	return e.ValueType
+}
+
+func (e *SubqueryExpression) AsAny() interface{} {
+	return e
+}
+
+func (e *SubqueryExpression) IsConst() bool {
+	return false
+}
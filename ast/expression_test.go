@@ -409,7 +409,7 @@ func TestInExpressionExprType(t *testing.T) {
 	}
 
 	ret := expr.ExprType(scope)
-	assert.Equal(t, true, ret.IsText())
+	assert.Equal(t, true, ret.IsBool())
 }
 
 func TestIsNullExpressionKind(t *testing.T) {
@@ -448,3 +448,24 @@ func TestNullExpressionExprType(t *testing.T) {
 	ret := expr.ExprType(scope)
 	assert.Equal(t, true, ret.IsNull())
 }
+
+func TestSubqueryExpressionKind(t *testing.T) {
+	expr := &SubqueryExpression{}
+	assert.Equal(t, ExprSubquery, expr.Kind())
+}
+
+func TestSubqueryExpressionExprType(t *testing.T) {
+	expr := &SubqueryExpression{
+		Query:     &GQLQuery{},
+		ValueType: Integer{},
+	}
+
+	scope := &Environment{
+		Globals:      make(map[string]Value),
+		GlobalsTypes: make(map[string]DataType),
+		Scopes:       make(map[string]DataType),
+	}
+
+	ret := expr.ExprType(scope)
+	assert.Equal(t, true, ret.IsInt())
+}
@@ -44,6 +44,7 @@ var Functions = map[string]Function{
 	"concat_ws":  textConcatWs,
 	"unicode":    textUnicode,
 	"strcmp":     textStrcmp,
+	"trailer":    textTrailer,
 
 	// Date functions
 	"current_date":      dateCurrentDate,
@@ -108,6 +109,7 @@ var Prototypes = map[string]Prototype{
 	"concat_ws":  {Parameters: []DataType{Text{}, Any{}, Any{}, Varargs{Any{}}}, Result: Text{}},
 	"unicode":    {Parameters: []DataType{Text{}}, Result: Integer{}},
 	"strcmp":     {Parameters: []DataType{Text{}, Text{}}, Result: Integer{}},
+	"trailer":    {Parameters: []DataType{Text{}, Text{}}, Result: Text{}},
 
 	// Date functions
 	"current_date":      {Parameters: []DataType{}, Result: Date{}},
@@ -116,9 +118,9 @@ var Prototypes = map[string]Prototype{
 	"now":               {Parameters: []DataType{}, Result: DateTime{}},
 	"makedate":          {Parameters: []DataType{Integer{}, Integer{}}, Result: Date{}},
 	"maketime":          {Parameters: []DataType{Integer{}, Integer{}, Integer{}}, Result: Time{}},
-	"day":               {Parameters: []DataType{Date{}}, Result: Integer{}},
-	"dayname":           {Parameters: []DataType{Date{}}, Result: Text{}},
-	"monthname":         {Parameters: []DataType{Date{}}, Result: Text{}},
+	"day":               {Parameters: []DataType{Variant{Date{}, DateTime{}}}, Result: Integer{}},
+	"dayname":           {Parameters: []DataType{Variant{Date{}, DateTime{}}}, Result: Text{}},
+	"monthname":         {Parameters: []DataType{Variant{Date{}, DateTime{}}}, Result: Text{}},
 	"hour":              {Parameters: []DataType{DateTime{}}, Result: Integer{}},
 	"isdate":            {Parameters: []DataType{Any{}}, Result: Boolean{}},
 
@@ -416,6 +418,10 @@ func textStrcmp(inputs []Value) Value {
 	}
 }
 
+func textTrailer(inputs []Value) Value {
+	return TextValue{GetTrailerValue(inputs[0].AsText(), inputs[1].AsText())}
+}
+
 // Date functions
 
 func dateCurrentDate(inputs []Value) Value {
@@ -292,6 +292,19 @@ func TestTextStrcmp(t *testing.T) {
 	assert.Equal(t, int64(1), ret.AsInt())
 }
 
+func TestTextTrailer(t *testing.T) {
+	var buf []Value
+
+	buf = append(buf, TextValue{"Fix crash\n\nSigned-off-by: name <name@example.com>\n"}, TextValue{"signed-off-by"})
+	ret := textTrailer(buf)
+	assert.Equal(t, "name <name@example.com>", ret.AsText())
+
+	buf = nil
+	buf = append(buf, TextValue{"Fix crash\n"}, TextValue{"Signed-off-by"})
+	ret = textTrailer(buf)
+	assert.Equal(t, "", ret.AsText())
+}
+
 // Date functions
 
 func TestDateCurrentDate(t *testing.T) {
@@ -352,6 +365,11 @@ func TestDateDayname(t *testing.T) {
 	ret := dateDayname(buf)
 	fmt.Printf("dateDayname: %s", ret.AsText())
 	assert.NotEqual(t, "", ret.AsText())
+
+	// DateTime values are accepted
+	ret = dateDayname([]Value{DateTimeValue{1705117592}})
+	assert.Equal(t, DateToDayName(1705117592), ret.AsText())
+	assert.Equal(t, true, Prototypes["dayname"].Parameters[0].Equal(DateTime{}))
 }
 
 func TestDateMonthname(t *testing.T) {
@@ -15,7 +15,11 @@ const (
 	OrderBy
 	GroupBy
 	AggregateFunction
+	WindowFunction
 	GlobalVariable
+	Insert
+	Update
+	Delete
 )
 
 type Statement interface {
@@ -26,6 +30,14 @@ type Statement interface {
 type Query struct {
 	Select                    *GQLQuery
 	GlobalVariableDeclaration *GlobalVariableStatement
+	Insert                    *InsertStatement
+	Update                    *UpdateStatement
+	Delete                    *DeleteStatement
+	Subqueries                []*SubqueryExpression
+}
+
+func (q *Query) IsMutation() bool {
+	return q.Insert != nil || q.Update != nil || q.Delete != nil
 }
 
 type GQLQuery struct {
@@ -33,14 +45,79 @@ type GQLQuery struct {
 	HasAggregationFunction bool
 	HasGroupByStatement    bool
 	HiddenSelections       []string
+	Subqueries             []*SubqueryExpression
+	SetOperations          []SetOperation
+}
+
+type SetOperator int
+
+const (
+	SOUnion SetOperator = iota
+	SOIntersect
+	SOExcept
+)
+
+// SetOperation combines the rows of a query with the rows of another select query, `ALL` keeps the duplicated rows
+type SetOperation struct {
+	Operator SetOperator
+	IsAll    bool
+	Query    *GQLQuery
+	Env      *Environment
 }
 
 type SelectStatement struct {
 	TableName    string
+	TableAlias   string
+	DerivedTable *DerivedTable
+	Joins        []Join
 	FieldsNames  []string
 	FieldsValues []Expression
 	AliasTable   map[string]string
 	IsDistinct   bool
+	// Condition is the `WHERE` condition of the query, the tables can use it to skip the rows which can't match it
+	Condition Expression
+}
+
+// TableReference returns the name used to qualify the fields of the selected table
+func (s *SelectStatement) TableReference() string {
+	if s.TableAlias != "" {
+		return s.TableAlias
+	}
+	return s.TableName
+}
+
+// DerivedTable is a table selected from the result of a query like `FROM (SELECT ...) AS t`, a common table
+// expression defined by `WITH t AS (SELECT ...)` keeps its result for each repository so it's evaluated only once
+type DerivedTable struct {
+	Query       *GQLQuery
+	Env         *Environment
+	FieldsNames []string
+	FieldsTypes map[string]DataType
+	IsCommon    bool
+	Results     map[string]*GitQLObject
+}
+
+type JoinKind int
+
+const (
+	InnerJoin JoinKind = iota
+	LeftJoin
+)
+
+type Join struct {
+	Kind         JoinKind
+	TableName    string
+	TableAlias   string
+	DerivedTable *DerivedTable
+	Condition    Expression
+}
+
+// TableReference returns the name used to qualify the fields of the joined table
+func (j *Join) TableReference() string {
+	if j.TableAlias != "" {
+		return j.TableAlias
+	}
+	return j.TableName
 }
 
 func (s *SelectStatement) AsAny() reflect.Value {
@@ -119,8 +196,9 @@ func (s *OrderByStatement) Kind() StatementKind {
 	return OrderBy
 }
 
+// GroupByStatement groups the rows with equal values of all the expressions
 type GroupByStatement struct {
-	FieldName string
+	Values []Expression
 }
 
 func (s *GroupByStatement) AsAny() reflect.Value {
@@ -151,6 +229,38 @@ func (s *AggregationsStatement) Kind() StatementKind {
 	return AggregateFunction
 }
 
+// Window is the `OVER` clause of a window function, the rows are partitioned by the `PARTITION BY` values and each
+// partition is sorted by the `ORDER BY` arguments
+type Window struct {
+	PartitionBy []Expression
+	OrderBy     *OrderByStatement
+}
+
+type WindowFunctionCall struct {
+	Name      string
+	Arguments []Expression
+	Window    Window
+}
+
+// WindowValue is a column computed by a window function or by an expression which uses window functions columns
+type WindowValue struct {
+	Expression Expression
+	Function   *WindowFunctionCall
+}
+
+// WindowFunctionsStatement computes the window functions columns after the rows are grouped and filtered by `HAVING`
+type WindowFunctionsStatement struct {
+	Windows map[string]WindowValue
+}
+
+func (s *WindowFunctionsStatement) AsAny() reflect.Value {
+	return reflect.ValueOf(s)
+}
+
+func (s *WindowFunctionsStatement) Kind() StatementKind {
+	return WindowFunction
+}
+
 type GlobalVariableStatement struct {
 	Name  string
 	Value Expression
@@ -163,3 +273,46 @@ func (s *GlobalVariableStatement) AsAny() reflect.Value {
 func (s *GlobalVariableStatement) Kind() StatementKind {
 	return GlobalVariable
 }
+
+type InsertStatement struct {
+	TableName   string
+	FieldsNames []string
+	Values      [][]Expression
+	Select      *GQLQuery
+}
+
+func (s *InsertStatement) AsAny() reflect.Value {
+	return reflect.ValueOf(s)
+}
+
+func (s *InsertStatement) Kind() StatementKind {
+	return Insert
+}
+
+type UpdateStatement struct {
+	TableName    string
+	FieldsNames  []string
+	FieldsValues []Expression
+	Where        *WhereStatement
+}
+
+func (s *UpdateStatement) AsAny() reflect.Value {
+	return reflect.ValueOf(s)
+}
+
+func (s *UpdateStatement) Kind() StatementKind {
+	return Update
+}
+
+type DeleteStatement struct {
+	TableName string
+	Where     *WhereStatement
+}
+
+func (s *DeleteStatement) AsAny() reflect.Value {
+	return reflect.ValueOf(s)
+}
+
+func (s *DeleteStatement) Kind() StatementKind {
+	return Delete
+}
@@ -2,12 +2,30 @@ package ast
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSelectStatementKind(t *testing.T) {
 	t.Skip("Skipping TestSelectStatementKind.")
 }
 
+func TestSelectStatementTableReference(t *testing.T) {
+	statement := SelectStatement{TableName: "commits"}
+	assert.Equal(t, "commits", statement.TableReference())
+
+	statement.TableAlias = "c"
+	assert.Equal(t, "c", statement.TableReference())
+}
+
+func TestJoinTableReference(t *testing.T) {
+	join := Join{Kind: LeftJoin, TableName: "diffs"}
+	assert.Equal(t, "diffs", join.TableReference())
+
+	join.TableAlias = "d"
+	assert.Equal(t, "d", join.TableReference())
+}
+
 func TestWhereStatementKind(t *testing.T) {
 	t.Skip("Skipping TestWhereStatementKind.")
 }
@@ -36,6 +54,34 @@ func TestAggregationFunctionStatementKind(t *testing.T) {
 	t.Skip("Skipping TestAggregationFunctionStatementKind.")
 }
 
+func TestWindowFunctionsStatementKind(t *testing.T) {
+	statement := WindowFunctionsStatement{}
+	assert.Equal(t, WindowFunction, statement.Kind())
+}
+
 func TestGlobalVariableStatementKind(t *testing.T) {
 	t.Skip("Skipping TestGlobalVariableStatementKind.")
 }
+
+func TestInsertStatementKind(t *testing.T) {
+	statement := InsertStatement{}
+	assert.Equal(t, Insert, statement.Kind())
+}
+
+func TestUpdateStatementKind(t *testing.T) {
+	statement := UpdateStatement{}
+	assert.Equal(t, Update, statement.Kind())
+}
+
+func TestDeleteStatementKind(t *testing.T) {
+	statement := DeleteStatement{}
+	assert.Equal(t, Delete, statement.Kind())
+}
+
+func TestQueryIsMutation(t *testing.T) {
+	query := Query{Select: &GQLQuery{}}
+	assert.Equal(t, false, query.IsMutation())
+
+	query = Query{Delete: &DeleteStatement{TableName: "refs"}}
+	assert.Equal(t, true, query.IsMutation())
+}
@@ -0,0 +1,160 @@
+package ast
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	trailerCommentPrefix = "#"
+	trailerDivider       = "---"
+)
+
+var (
+	// Trailers which are recognized even if the trailers block has other lines like git
+	trailerGitGeneratedPrefixes = []string{"Signed-off-by: ", "(cherry picked from commit "}
+	trailerPattern              = regexp.MustCompile(`^([A-Za-z0-9-]+)[ \t]*:[ \t]*(.*)$`)
+)
+
+// Trailer is a `Key: value` line of the trailers block of a commit message
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// ParseTrailers returns the trailers of the commit message in their order following the `git interpret-trailers`
+// rules, the trailers block is the last paragraph after the subject which has only trailers or at least a quarter of
+// trailers with a git generated one, the continuation lines starting with a whitespace are unfolded into the value
+// https://git-scm.com/docs/git-interpret-trailers
+func ParseTrailers(message string) []Trailer {
+	lines := strings.Split(message, "\n")
+
+	// The patch after the `---` divider is not a part of the message
+	end := len(lines)
+	for index, line := range lines {
+		if line == trailerDivider || strings.HasPrefix(line, trailerDivider+" ") || strings.HasPrefix(line, trailerDivider+"\t") {
+			end = index
+			break
+		}
+	}
+
+	start, ok := trailersBlockStart(lines[:end])
+	if !ok {
+		return nil
+	}
+
+	var trailers []Trailer
+	isContinuable := false
+	for _, line := range lines[start:end] {
+		line = strings.TrimRight(line, " \t\r")
+		if strings.HasPrefix(line, trailerCommentPrefix) {
+			continue
+		}
+
+		if isContinuable && isTrailerContinuation(line) {
+			last := &trailers[len(trailers)-1]
+			last.Value = strings.TrimSpace(last.Value + " " + strings.TrimSpace(line))
+			continue
+		}
+
+		matches := trailerPattern.FindStringSubmatch(line)
+		if matches == nil {
+			isContinuable = false
+			continue
+		}
+		trailers = append(trailers, Trailer{Key: matches[1], Value: matches[2]})
+		isContinuable = true
+	}
+
+	return trailers
+}
+
+// trailersBlockStart returns the index of the first line of the trailers block, it is not found if the message has
+// only a subject paragraph or if its last paragraph has too many lines which are not trailers
+func trailersBlockStart(lines []string) (int, bool) {
+	// The subject paragraph is never a trailers block
+	subjectEnd := -1
+	for index, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			subjectEnd = index
+			break
+		}
+	}
+	if subjectEnd == -1 {
+		return 0, false
+	}
+
+	var trailerLines, nonTrailerLines, continuationLines int
+	isRecognized := false
+	isBlank := true
+	for index := len(lines) - 1; index > subjectEnd; index-- {
+		line := strings.TrimRight(lines[index], " \t\r")
+		if strings.HasPrefix(line, trailerCommentPrefix) {
+			continue
+		}
+
+		if line == "" {
+			if isBlank {
+				continue
+			}
+			return index + 1, isTrailersBlock(trailerLines, nonTrailerLines, isRecognized)
+		}
+		isBlank = false
+
+		if isGitGeneratedTrailer(line) {
+			trailerLines++
+			continuationLines = 0
+			isRecognized = true
+			continue
+		}
+
+		if trailerPattern.MatchString(line) {
+			trailerLines++
+			continuationLines = 0
+			continue
+		}
+
+		if isTrailerContinuation(line) {
+			continuationLines++
+			continue
+		}
+
+		nonTrailerLines += continuationLines + 1
+		continuationLines = 0
+	}
+
+	return subjectEnd + 1, !isBlank && isTrailersBlock(trailerLines, nonTrailerLines, isRecognized)
+}
+
+// nolint:gomnd
+func isTrailersBlock(trailerLines, nonTrailerLines int, isRecognized bool) bool {
+	if trailerLines > 0 && nonTrailerLines == 0 {
+		return true
+	}
+	return isRecognized && trailerLines*3 >= nonTrailerLines
+}
+
+func isGitGeneratedTrailer(line string) bool {
+	for _, prefix := range trailerGitGeneratedPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTrailerContinuation(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// GetTrailerValue returns the value of the first trailer with the key which is compared case-insensitively like git,
+// the trailing colon of the key is optional and the value is empty if the message has no such trailer
+func GetTrailerValue(message, key string) string {
+	key = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(key), ":"))
+	for _, trailer := range ParseTrailers(message) {
+		if strings.EqualFold(trailer.Key, key) {
+			return trailer.Value
+		}
+	}
+	return ""
+}
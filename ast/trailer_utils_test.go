@@ -0,0 +1,64 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrailers(t *testing.T) {
+	message := "Fix crash\n\nDescription: not a trailer\n\nSigned-off-by: name <name@example.com>\nReviewed-by : reviewer\n" +
+		"Bug: 123,\n 456\nCo-authored-by: author <author@example.com>\n"
+	assert.Equal(t, []Trailer{
+		{Key: "Signed-off-by", Value: "name <name@example.com>"},
+		{Key: "Reviewed-by", Value: "reviewer"},
+		{Key: "Bug", Value: "123, 456"},
+		{Key: "Co-authored-by", Value: "author <author@example.com>"},
+	}, ParseTrailers(message))
+
+	// A paragraph with a git generated trailer may have other lines
+	message = "Fix crash\n\nCherry-pick of the fix\n(cherry picked from commit 1234567)\nSigned-off-by: name <name@example.com>"
+	assert.Equal(t, []Trailer{{Key: "Signed-off-by", Value: "name <name@example.com>"}}, ParseTrailers(message))
+
+	// A paragraph with another line and no git generated trailer has no trailers
+	assert.Equal(t, 0, len(ParseTrailers("Fix crash\n\nSee the issue\nBug: 123\n")))
+
+	// The patch after the divider is ignored
+	assert.Equal(t, []Trailer{{Key: "Bug", Value: "123"}}, ParseTrailers("Fix crash\n\nBug: 123\n---\n file | 2 +-\n"))
+
+	// The subject is never a trailer
+	assert.Equal(t, 0, len(ParseTrailers("Bug: 123\n")))
+	assert.Equal(t, 0, len(ParseTrailers("Bug: 123\n\n\n")))
+	assert.Equal(t, 0, len(ParseTrailers("")))
+}
+
+func TestTrailersBlockStart(t *testing.T) {
+	start, ok := trailersBlockStart([]string{"Fix crash", "", "Description", "", "Bug: 123", ""})
+	assert.True(t, ok)
+	assert.Equal(t, 4, start)
+
+	start, ok = trailersBlockStart([]string{"Fix crash", "", "Bug: 123"})
+	assert.True(t, ok)
+	assert.Equal(t, 2, start)
+
+	_, ok = trailersBlockStart([]string{"Fix crash", "", "Description", "Bug: 123"})
+	assert.False(t, ok)
+
+	_, ok = trailersBlockStart([]string{"Fix crash"})
+	assert.False(t, ok)
+}
+
+func TestIsTrailersBlock(t *testing.T) {
+	assert.True(t, isTrailersBlock(2, 0, false))
+	assert.False(t, isTrailersBlock(0, 0, false))
+	assert.False(t, isTrailersBlock(1, 1, false))
+	assert.True(t, isTrailersBlock(1, 3, true))
+	assert.False(t, isTrailersBlock(1, 4, true))
+}
+
+func TestGetTrailerValue(t *testing.T) {
+	message := "Fix crash\n\nSigned-off-by: first <first@example.com>\nSigned-off-by: second <second@example.com>\nBug: 123\n"
+	assert.Equal(t, "first <first@example.com>", GetTrailerValue(message, "Signed-off-by"))
+	assert.Equal(t, "123", GetTrailerValue(message, "bug:"))
+	assert.Equal(t, "", GetTrailerValue(message, "Change-Id"))
+}
@@ -41,22 +41,98 @@ type Optional struct{ DataType }
 type Varargs struct{ DataType }
 
 var TablesFieldsTypes = map[string]DataType{
-	"change_id":     Text{},
-	"commit_id":     Text{},
-	"title":         Text{},
-	"message":       Text{},
-	"name":          Text{},
-	"full_name":     Text{},
-	"insertions":    Integer{},
-	"deletions":     Integer{},
-	"files_changed": Integer{},
-	"email":         Text{},
-	"type":          Text{},
-	"datetime":      DateTime{},
-	"is_head":       Boolean{},
-	"is_remote":     Boolean{},
-	"commit_count":  Integer{},
-	"repo":          Text{},
+	"change_id":        Text{},
+	"commit_id":        Text{},
+	"title":            Text{},
+	"message":          Text{},
+	"name":             Text{},
+	"full_name":        Text{},
+	"insertions":       Integer{},
+	"deletions":        Integer{},
+	"files_changed":    Integer{},
+	"email":            Text{},
+	"type":             Text{},
+	"datetime":         DateTime{},
+	"is_head":          Boolean{},
+	"is_remote":        Boolean{},
+	"commit_count":     Integer{},
+	"path":             Text{},
+	"line_no":          Integer{},
+	"line":             Text{},
+	"extension":        Text{},
+	"size":             Integer{},
+	"mode":             Text{},
+	"blob_id":          Text{},
+	"is_binary":        Boolean{},
+	"revision":         Text{},
+	"old_path":         Text{},
+	"change_type":      Text{},
+	"hunk_index":       Integer{},
+	"old_start":        Integer{},
+	"old_lines":        Integer{},
+	"new_start":        Integer{},
+	"new_lines":        Integer{},
+	"kind":             Text{},
+	"content":          Text{},
+	"upstream":         Text{},
+	"ahead":            Integer{},
+	"behind":           Integer{},
+	"url":              Text{},
+	"fetch_refspecs":   Text{},
+	"push_url":         Text{},
+	"ref":              Text{},
+	"index":            Integer{},
+	"old_id":           Text{},
+	"new_id":           Text{},
+	"branch":           Text{},
+	"staging":          Text{},
+	"worktree":         Text{},
+	"is_untracked":     Boolean{},
+	"is_initialized":   Boolean{},
+	"notes_ref":        Text{},
+	"note":             Text{},
+	"committer_name":   Text{},
+	"committer_email":  Text{},
+	"commit_datetime":  DateTime{},
+	"parent_ids":       Text{},
+	"parent_count":     Integer{},
+	"is_merge":         Boolean{},
+	"tree_id":          Text{},
+	"signature":        Text{},
+	"is_signed":        Boolean{},
+	"tag_object_id":    Text{},
+	"is_annotated":     Boolean{},
+	"tagger_name":      Text{},
+	"tagger_email":     Text{},
+	"change_number":    Integer{},
+	"patchset_number":  Integer{},
+	"patchset_count":   Integer{},
+	"current_patchset": Integer{},
+	"uploader_name":    Text{},
+	"uploader_email":   Text{},
+	"label":            Text{},
+	"value":            Integer{},
+	"voter":            Text{},
+	"status":           Text{},
+	"topic":            Text{},
+	"hashtags":         Text{},
+	"submitter":        Text{},
+	"key":              Text{},
+	"position":         Integer{},
+	"repo":             Text{},
+}
+
+// TablesFieldsTypesOverrides are the types of the table fields which differ from the common type of the field name
+var TablesFieldsTypesOverrides = map[string]map[string]DataType{
+	"commit_trailers": {"value": Text{}},
+}
+
+// TableFieldType returns the type of the field in the table
+func TableFieldType(tableName, fieldName string) DataType {
+	if fieldType, ok := TablesFieldsTypesOverrides[tableName][fieldName]; ok {
+		return fieldType
+	}
+	return TablesFieldsTypes[fieldName]
 }
 
 // Any implementation
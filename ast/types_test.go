@@ -254,3 +254,10 @@ func TestDatatypeIsVarargs(t *testing.T) {
 	ret := datatype.IsVarargs()
 	assert.Equal(t, true, ret)
 }
+
+func TestTableFieldType(t *testing.T) {
+	assert.Equal(t, Text{}, TableFieldType("commit_trailers", "value"))
+	assert.Equal(t, Integer{}, TableFieldType("gerrit_votes", "value"))
+	assert.Equal(t, Text{}, TableFieldType("commit_trailers", "commit_id"))
+	assert.Nil(t, TableFieldType("commit_trailers", "unknown"))
+}
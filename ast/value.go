@@ -451,7 +451,15 @@ func (v BooleanValue) Equals(other Value) bool {
 }
 
 func (v BooleanValue) Compare(other Value) Ordering {
-	return Equal
+	if !other.DataType().IsBool() || other.AsBool() == v.AsBool() {
+		return Equal
+	}
+
+	// false is less than true
+	if v.AsBool() {
+		return Less
+	}
+	return Greater
 }
 
 func (v BooleanValue) Plus(other Value) (Value, error) {
@@ -582,7 +590,7 @@ func (v DateTimeValue) AsDateTime() int64 {
 }
 
 func (v DateTimeValue) AsDate() int64 {
-	return 0
+	return v.Value
 }
 
 func (v DateTimeValue) AsTime() string {
@@ -498,6 +498,12 @@ func TestBooleanValueCompare(t *testing.T) {
 	other := BooleanValue{true}
 	ret := value.Compare(other)
 	assert.Equal(t, Ordering(Equal), ret)
+
+	ret = value.Compare(BooleanValue{false})
+	assert.Equal(t, Ordering(Less), ret)
+
+	ret = BooleanValue{false}.Compare(other)
+	assert.Equal(t, Ordering(Greater), ret)
 }
 
 func TestBooleanValuePlus(t *testing.T) {
@@ -636,7 +642,9 @@ func TestDateTimeValueAsDateTime(t *testing.T) {
 }
 
 func TestDateTimeValueAsDate(t *testing.T) {
-	assert.Equal(t, nil, nil)
+	value := DateTimeValue{1704890191}
+	ret := value.AsDate()
+	assert.Equal(t, int64(1704890191), ret)
 }
 
 func TestDateTimeValueAsTime(t *testing.T) {
@@ -0,0 +1,146 @@
+package ast
+
+// WindowPartition is the rows of a window partition sorted by the window order, each row has the values of the window
+// function arguments and the range of its peers, the rows with the same order values. The frame of each row starts at
+// the first row of the partition and ends at its last peer
+type WindowPartition struct {
+	Arguments  [][]Value
+	PeersStart []int
+	PeersEnd   []int
+}
+
+func (p *WindowPartition) Len() int {
+	return len(p.Arguments)
+}
+
+type WindowFunc func(*WindowPartition) []Value
+
+// WindowFunctionPrototype parameters can end with optional parameters, nil result means the type of the first argument
+type WindowFunctionPrototype struct {
+	Parameters []DataType
+	Result     DataType
+}
+
+var WindowFunctions = map[string]WindowFunc{
+	"row_number":  windowRowNumber,
+	"rank":        windowRank,
+	"dense_rank":  windowDenseRank,
+	"lag":         windowLag,
+	"lead":        windowLead,
+	"first_value": windowFirstValue,
+	"last_value":  windowLastValue,
+	"sum":         windowSum,
+	"count":       windowCount,
+}
+
+var WindowFunctionsProtos = map[string]WindowFunctionPrototype{
+	"row_number":  {Parameters: []DataType{}, Result: Integer{}},
+	"rank":        {Parameters: []DataType{}, Result: Integer{}},
+	"dense_rank":  {Parameters: []DataType{}, Result: Integer{}},
+	"lag":         {Parameters: []DataType{Any{}, Optional{Integer{}}, Optional{Any{}}}},
+	"lead":        {Parameters: []DataType{Any{}, Optional{Integer{}}, Optional{Any{}}}},
+	"first_value": {Parameters: []DataType{Any{}}},
+	"last_value":  {Parameters: []DataType{Any{}}},
+	"sum":         {Parameters: []DataType{Integer{}}, Result: Integer{}},
+	"count":       {Parameters: []DataType{Any{}}, Result: Integer{}},
+}
+
+func windowRowNumber(partition *WindowPartition) []Value {
+	values := make([]Value, partition.Len())
+	for index := range values {
+		values[index] = IntegerValue{Value: int64(index + 1)}
+	}
+	return values
+}
+
+func windowRank(partition *WindowPartition) []Value {
+	values := make([]Value, partition.Len())
+	for index := range values {
+		values[index] = IntegerValue{Value: int64(partition.PeersStart[index] + 1)}
+	}
+	return values
+}
+
+func windowDenseRank(partition *WindowPartition) []Value {
+	values := make([]Value, partition.Len())
+	var rank int64
+	for index := range values {
+		if partition.PeersStart[index] == index {
+			rank++
+		}
+		values[index] = IntegerValue{Value: rank}
+	}
+	return values
+}
+
+func windowLag(partition *WindowPartition) []Value {
+	return windowOffsetValues(partition, -1)
+}
+
+func windowLead(partition *WindowPartition) []Value {
+	return windowOffsetValues(partition, 1)
+}
+
+// windowOffsetValues returns the value of the row before or after each row by the offset argument, or the default
+// argument if there is no such row
+func windowOffsetValues(partition *WindowPartition, direction int) []Value {
+	values := make([]Value, partition.Len())
+	for index, arguments := range partition.Arguments {
+		offset := 1
+		if len(arguments) > 1 {
+			offset = int(arguments[1].AsInt())
+		}
+
+		var defaultValue Value = NullValue{}
+		if len(arguments) > 2 {
+			defaultValue = arguments[2]
+		}
+
+		target := index + direction*offset
+		if target >= 0 && target < partition.Len() {
+			values[index] = partition.Arguments[target][0]
+		} else {
+			values[index] = defaultValue
+		}
+	}
+	return values
+}
+
+func windowFirstValue(partition *WindowPartition) []Value {
+	values := make([]Value, partition.Len())
+	for index := range values {
+		values[index] = partition.Arguments[0][0]
+	}
+	return values
+}
+
+func windowLastValue(partition *WindowPartition) []Value {
+	values := make([]Value, partition.Len())
+	for index := range values {
+		values[index] = partition.Arguments[partition.PeersEnd[index]][0]
+	}
+	return values
+}
+
+func windowSum(partition *WindowPartition) []Value {
+	sums := make([]int64, partition.Len())
+	var sum int64
+	for index, arguments := range partition.Arguments {
+		sum += arguments[0].AsInt()
+		sums[index] = sum
+	}
+
+	values := make([]Value, partition.Len())
+	for index := range values {
+		values[index] = IntegerValue{Value: sums[partition.PeersEnd[index]]}
+	}
+	return values
+}
+
+func windowCount(partition *WindowPartition) []Value {
+	values := make([]Value, partition.Len())
+	for index := range values {
+		values[index] = IntegerValue{Value: int64(partition.PeersEnd[index] + 1)}
+	}
+	return values
+}
@@ -0,0 +1,105 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newWindowPartition returns a partition of the arguments where the second and third rows are peers
+func newWindowPartition(arguments ...[]Value) *WindowPartition {
+	return &WindowPartition{
+		Arguments:  arguments,
+		PeersStart: []int{0, 1, 1, 3},
+		PeersEnd:   []int{0, 2, 2, 3},
+	}
+}
+
+func TestWindowRowNumber(t *testing.T) {
+	partition := newWindowPartition([]Value{}, []Value{}, []Value{}, []Value{})
+	values := windowRowNumber(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{2}, IntegerValue{3}, IntegerValue{4}}, values)
+}
+
+func TestWindowRank(t *testing.T) {
+	partition := newWindowPartition([]Value{}, []Value{}, []Value{}, []Value{})
+	values := windowRank(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{2}, IntegerValue{2}, IntegerValue{4}}, values)
+}
+
+func TestWindowDenseRank(t *testing.T) {
+	partition := newWindowPartition([]Value{}, []Value{}, []Value{}, []Value{})
+	values := windowDenseRank(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{2}, IntegerValue{2}, IntegerValue{3}}, values)
+}
+
+func TestWindowLag(t *testing.T) {
+	partition := newWindowPartition(
+		[]Value{TextValue{"a"}},
+		[]Value{TextValue{"b"}},
+		[]Value{TextValue{"c"}},
+		[]Value{TextValue{"d"}},
+	)
+	values := windowLag(partition)
+	assert.Equal(t, []Value{NullValue{}, TextValue{"a"}, TextValue{"b"}, TextValue{"c"}}, values)
+
+	// Offset and default value arguments
+	partition = newWindowPartition(
+		[]Value{TextValue{"a"}, IntegerValue{2}, TextValue{"none"}},
+		[]Value{TextValue{"b"}, IntegerValue{2}, TextValue{"none"}},
+		[]Value{TextValue{"c"}, IntegerValue{2}, TextValue{"none"}},
+		[]Value{TextValue{"d"}, IntegerValue{2}, TextValue{"none"}},
+	)
+	values = windowLag(partition)
+	assert.Equal(t, []Value{TextValue{"none"}, TextValue{"none"}, TextValue{"a"}, TextValue{"b"}}, values)
+}
+
+func TestWindowLead(t *testing.T) {
+	partition := newWindowPartition(
+		[]Value{IntegerValue{1}},
+		[]Value{IntegerValue{2}},
+		[]Value{IntegerValue{3}},
+		[]Value{IntegerValue{4}},
+	)
+	values := windowLead(partition)
+	assert.Equal(t, []Value{IntegerValue{2}, IntegerValue{3}, IntegerValue{4}, NullValue{}}, values)
+}
+
+func TestWindowFirstValue(t *testing.T) {
+	partition := newWindowPartition(
+		[]Value{IntegerValue{1}},
+		[]Value{IntegerValue{2}},
+		[]Value{IntegerValue{3}},
+		[]Value{IntegerValue{4}},
+	)
+	values := windowFirstValue(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{1}, IntegerValue{1}, IntegerValue{1}}, values)
+}
+
+func TestWindowLastValue(t *testing.T) {
+	partition := newWindowPartition(
+		[]Value{IntegerValue{1}},
+		[]Value{IntegerValue{2}},
+		[]Value{IntegerValue{3}},
+		[]Value{IntegerValue{4}},
+	)
+	values := windowLastValue(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{3}, IntegerValue{3}, IntegerValue{4}}, values)
+}
+
+func TestWindowSum(t *testing.T) {
+	partition := newWindowPartition(
+		[]Value{IntegerValue{1}},
+		[]Value{IntegerValue{2}},
+		[]Value{IntegerValue{3}},
+		[]Value{IntegerValue{4}},
+	)
+	values := windowSum(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{6}, IntegerValue{6}, IntegerValue{10}}, values)
+}
+
+func TestWindowCount(t *testing.T) {
+	partition := newWindowPartition([]Value{}, []Value{}, []Value{}, []Value{})
+	values := windowCount(partition)
+	assert.Equal(t, []Value{IntegerValue{1}, IntegerValue{3}, IntegerValue{3}, IntegerValue{4}}, values)
+}
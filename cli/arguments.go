@@ -30,6 +30,9 @@ type Arguments struct {
 	Pagination   bool
 	PageSize     int
 	OutputFormat OutputFormat
+	DryRun       bool
+	TaggerName   string
+	TaggerEmail  string
 }
 
 // Command represents the possible GitQL commands
@@ -56,6 +59,9 @@ func NewArguments() Arguments {
 		Pagination:   false,
 		PageSize:     10,
 		OutputFormat: Render,
+		DryRun:       false,
+		TaggerName:   "",
+		TaggerEmail:  "",
 	}
 }
 
@@ -114,6 +120,21 @@ func ParseArguments(args []string) Command {
 		case "--analysis", "-a":
 			arguments.Analysis = true
 			argIndex++
+		case "--dry-run", "-d":
+			arguments.DryRun = true
+			argIndex++
+		case "--tagger", "-t":
+			argIndex++
+			if argIndex >= argsLen {
+				return Command{Error: fmt.Sprintf("Argument %s must be followed by the tagger identity", arg)}
+			}
+			name, email, ok := parseIdentity(args[argIndex])
+			if !ok {
+				return Command{Error: "Invalid tagger identity, expected `Name <email>`"}
+			}
+			arguments.TaggerName = name
+			arguments.TaggerEmail = email
+			argIndex++
 		case "--pagination", "-p":
 			arguments.Pagination = true
 			argIndex++
@@ -196,6 +217,8 @@ func PrintHelpList() {
 	fmt.Println("-r,  --repos <REPOS>        Path for local repositories to run query on")
 	fmt.Println("-q,  --query <GQL Query>    GitQL query to run on selected repositories")
 	fmt.Println("-m,  --mutate <GQL Mutate>  GitQL mutate to run on selected repositories")
+	fmt.Println("-d,  --dry-run              Print the changes of the mutate without applying them")
+	fmt.Println("-t,  --tagger <IDENTITY>    Set annotated tags and notes identity as `Name <email>`")
 	fmt.Println("-p,  --pagination           Enable print result with pagination")
 	fmt.Println("-ps, --pagesize             Set pagination page size [default: 10]")
 	fmt.Println("-o,  --output               Set output format [render, json, csv]")
@@ -214,3 +237,20 @@ func contains(arr []string, items ...string) bool {
 	}
 	return false
 }
+
+// parseIdentity splits a git identity with format `Name <email>`
+func parseIdentity(identity string) (name, email string, ok bool) {
+	start := strings.LastIndex(identity, "<")
+	end := strings.LastIndex(identity, ">")
+	if start <= 0 || end != len(identity)-1 || end-start < 2 {
+		return "", "", false
+	}
+
+	name = strings.TrimSpace(identity[:start])
+	email = strings.TrimSpace(identity[start+1 : end])
+	if name == "" || email == "" {
+		return "", "", false
+	}
+
+	return name, email, true
+}
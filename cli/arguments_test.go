@@ -72,6 +72,36 @@ func TestMutateArguments(t *testing.T) {
 	assert.Equal(t, true, ret)
 }
 
+func TestDryRunArguments(t *testing.T) {
+	actual := ParseArguments([]string{"ggql", "--mutate", "delete from refs", "--dry-run"})
+	assert.Equal(t, true, actual.MutateMode.Arguments.DryRun)
+
+	actual = ParseArguments([]string{"ggql", "--mutate", "delete from refs"})
+	assert.Equal(t, false, actual.MutateMode.Arguments.DryRun)
+}
+
+func TestTaggerArguments(t *testing.T) {
+	actual := ParseArguments([]string{"ggql", "--mutate", "delete from refs", "--tagger", "Name <name@example.com>"})
+	assert.Equal(t, "Name", actual.MutateMode.Arguments.TaggerName)
+	assert.Equal(t, "name@example.com", actual.MutateMode.Arguments.TaggerEmail)
+
+	actual = ParseArguments([]string{"ggql", "--tagger", "name@example.com"})
+	assert.Equal(t, "Invalid tagger identity, expected `Name <email>`", actual.Error)
+}
+
+func TestParseIdentity(t *testing.T) {
+	name, email, ok := parseIdentity("First Last <first@example.com>")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "First Last", name)
+	assert.Equal(t, "first@example.com", email)
+
+	_, _, ok = parseIdentity("<first@example.com>")
+	assert.Equal(t, false, ok)
+
+	_, _, ok = parseIdentity("First Last")
+	assert.Equal(t, false, ok)
+}
+
 func TestPaginationArguments(t *testing.T) {
 	t.Skip("Skipping TestPaginationArguments.")
 }
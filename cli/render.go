@@ -37,6 +37,7 @@ func RenderObjects(groups *ast.GitQLObject, hiddenSelections []string, paginatio
 	var err error
 	var tableHeaders []string
 	var titles []string
+	var indexes []int
 
 	if groups.Len() > 1 {
 		groups.Flat()
@@ -50,9 +51,10 @@ func RenderObjects(groups *ast.GitQLObject, hiddenSelections []string, paginatio
 	gqlGroupLen := gqlGroup.Len()
 
 	// Setup titles
-	for _, title := range groups.Titles {
+	for index, title := range groups.Titles {
 		if !contains(hiddenSelections, title) {
 			titles = append(titles, title)
+			indexes = append(indexes, index)
 		}
 	}
 
@@ -63,7 +65,7 @@ func RenderObjects(groups *ast.GitQLObject, hiddenSelections []string, paginatio
 
 	// Print all data without pagination
 	if !pagination || pageSize >= gqlGroupLen {
-		return printGroupAsTable(titles, tableHeaders, gqlGroup.Rows)
+		return printGroupAsTable(indexes, tableHeaders, gqlGroup.Rows)
 	}
 
 	// Setup the pagination mode
@@ -79,7 +81,7 @@ func RenderObjects(groups *ast.GitQLObject, hiddenSelections []string, paginatio
 		currentPageGroups := gqlGroup.Rows[startIndex:endIndex]
 
 		fmt.Printf("Page %d/%d\n", currentPage, numberOfPages)
-		err = printGroupAsTable(titles, tableHeaders, currentPageGroups)
+		err = printGroupAsTable(indexes, tableHeaders, currentPageGroups)
 		if err != nil {
 			break
 		}
@@ -98,13 +100,14 @@ func RenderObjects(groups *ast.GitQLObject, hiddenSelections []string, paginatio
 	return err
 }
 
-func printGroupAsTable(titles, tableHeaders []string, rows []ast.Row) error {
+// printGroupAsTable prints the rows values at the visible columns indexes
+func printGroupAsTable(indexes []int, tableHeaders []string, rows []ast.Row) error {
 	data := pterm.TableData{}
 	data = append(data, tableHeaders)
 
 	for _, row := range rows {
 		var buf []string
-		for index := range titles {
+		for _, index := range indexes {
 			buf = append(buf, row.Values[index].AsText())
 		}
 		data = append(data, buf)
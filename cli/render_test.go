@@ -46,7 +46,11 @@ func TestPrintGroupAsTable(t *testing.T) {
 		tableHeaders = append(tableHeaders, item)
 	}
 
-	err := printGroupAsTable(titles, tableHeaders, rows)
+	err := printGroupAsTable([]int{0, 1}, tableHeaders, rows)
+	assert.Equal(t, nil, err)
+
+	// Hidden columns are skipped
+	err = printGroupAsTable([]int{1}, tableHeaders[1:], rows)
 	assert.Equal(t, nil, err)
 }
 
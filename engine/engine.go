@@ -3,6 +3,7 @@ package engine
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -17,6 +18,7 @@ var gqlCommandsInOrder = []string{
 	"group",
 	"aggregation",
 	"having",
+	"window",
 	"order",
 	"offset",
 	"limit",
@@ -28,6 +30,7 @@ type EvaluationResult struct {
 		Str []string
 	}
 	SetGlobalVariable bool
+	AffectedRows      int64
 }
 
 func Evaluate(env *ast.Environment, repos []*git.Repository, query ast.Query) (EvaluationResult, error) {
@@ -35,6 +38,10 @@ func Evaluate(env *ast.Environment, repos []*git.Repository, query ast.Query) (E
 		return EvaluateSelectQuery(env, repos, *query.Select)
 	}
 
+	if query.IsMutation() {
+		return EvaluateMutateQuery(env, repos, query, MutationOptions{})
+	}
+
 	if query.GlobalVariableDeclaration != nil {
 		err := executeGlobalVariableStatement(env, query.GlobalVariableDeclaration)
 		if err != nil {
@@ -52,6 +59,10 @@ func EvaluateSelectQuery(
 	repos []*git.Repository,
 	query ast.GQLQuery,
 ) (EvaluationResult, error) {
+	if len(query.SetOperations) != 0 {
+		return evaluateSetOperations(env, repos, query)
+	}
+
 	var gitqlObject ast.GitQLObject
 	aliasTable := make(map[string]string)
 
@@ -59,7 +70,25 @@ func EvaluateSelectQuery(
 	statementsMap := query.Statements
 	firstRepo := repos[0]
 
+	if err := evaluateSubqueries(repos, query.Subqueries); err != nil {
+		return EvaluationResult{}, err
+	}
+
+	// The selected fields are renamed to their aliases after the `WHERE` statement, so the condition uses the fields
+	// names and the later statements can use the aliases
+	isAliasesApplied := false
+	applyAliases := func() {
+		if selectStatement, ok := statementsMap["select"].(*ast.SelectStatement); ok && !isAliasesApplied {
+			ApplySelectedFieldsAliases(&gitqlObject, selectStatement.AliasTable)
+		}
+		isAliasesApplied = true
+	}
+
 	for _, gqlCommand := range gqlCommandsInOrder {
+		if gqlCommand != "select" && gqlCommand != "where" {
+			applyAliases()
+		}
+
 		if statement, ok := statementsMap[gqlCommand]; ok {
 			switch gqlCommand {
 			case "select":
@@ -72,6 +101,7 @@ func EvaluateSelectQuery(
 					}
 
 					if gitqlObject.IsEmpty() || gitqlObject.Groups[0].IsEmpty() {
+						applyAliases()
 						return EvaluationResult{SelectedGroups: struct {
 							Obj ast.GitQLObject
 							Str []string
@@ -85,8 +115,14 @@ func EvaluateSelectQuery(
 					continue
 				}
 
+				// The repositories without the revision of the query are skipped
+				skippedRepos := 0
 				for _, repo := range repos {
 					err := ExecuteStatement(env, statement, repo, &gitqlObject, aliasTable, hiddenSelections)
+					if errors.Is(err, errUnknownRevision) && skippedRepos+1 < len(repos) {
+						skippedRepos++
+						continue
+					}
 					if err != nil {
 						return EvaluationResult{SelectedGroups: struct {
 							Obj ast.GitQLObject
@@ -100,6 +136,7 @@ func EvaluateSelectQuery(
 				}
 
 				if gitqlObject.IsEmpty() || gitqlObject.Groups[0].IsEmpty() {
+					applyAliases()
 					return EvaluationResult{SelectedGroups: struct {
 						Obj ast.GitQLObject
 						Str []string
@@ -121,21 +158,15 @@ func EvaluateSelectQuery(
 				}
 			}
 		}
-	}
 
-	if len(gitqlObject.Groups) > 1 {
-		for _, group := range gitqlObject.Groups {
-			if len(group.Rows) > 1 {
-				group.Rows = group.Rows[:1]
-			}
-		}
-	} else if len(gitqlObject.Groups) == 1 && !query.HasGroupByStatement && query.HasAggregationFunction {
-		group := &gitqlObject.Groups[0]
-		if len(group.Rows) > 1 {
-			group.Rows = group.Rows[:1]
+		// After the aggregations each group is represented by its first row
+		if gqlCommand == "aggregation" && (query.HasGroupByStatement || query.HasAggregationFunction) {
+			ApplyGroupsFirstRow(&gitqlObject)
 		}
 	}
 
+	applyAliases()
+
 	return EvaluationResult{SelectedGroups: struct {
 		Obj ast.GitQLObject
 		Str []string
@@ -146,6 +177,21 @@ func EvaluateSelectQuery(
 	}, nil
 }
 
+func ApplySelectedFieldsAliases(gitqlObject *ast.GitQLObject, aliasTable map[string]string) {
+	for index, title := range gitqlObject.Titles {
+		gitqlObject.Titles[index] = GetColumnName(aliasTable, title)
+	}
+}
+
+func ApplyGroupsFirstRow(gitqlObject *ast.GitQLObject) {
+	for index := range gitqlObject.Groups {
+		group := &gitqlObject.Groups[index]
+		if len(group.Rows) > 1 {
+			group.Rows = group.Rows[:1]
+		}
+	}
+}
+
 func ApplyDistinctOnObjectsGroup(gitqlObject *ast.GitQLObject, hiddenSelections []string) {
 	if gitqlObject.IsEmpty() {
 		return
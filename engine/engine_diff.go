@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+const (
+	hunkContextLines = 3
+)
+
+// diffLine is a line of the file patch with the number of the old and new lines before it
+type diffLine struct {
+	operation   diff.Operation
+	text        string
+	oldPrevious int
+	newPrevious int
+}
+
+// diffHunk is a group of changed lines with their context lines like the unified diff hunks
+type diffHunk struct {
+	oldStart int
+	oldLines int
+	newStart int
+	newLines int
+	lines    []diffLine
+}
+
+// Header returns the unified diff header of the hunk
+func (h *diffHunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.oldStart, h.oldLines), hunkRange(h.newStart, h.newLines))
+}
+
+func hunkRange(start, lines int) string {
+	if lines == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, lines)
+}
+
+// diffLines splits the chunks of the file patch into lines
+func diffLines(chunks []diff.Chunk) []diffLine {
+	var lines []diffLine
+	var oldPrevious, newPrevious int
+
+	for _, chunk := range chunks {
+		texts := strings.SplitAfter(chunk.Content(), "\n")
+		if texts[len(texts)-1] == "" {
+			texts = texts[:len(texts)-1]
+		}
+
+		for _, text := range texts {
+			lines = append(lines, diffLine{
+				operation:   chunk.Type(),
+				text:        strings.TrimSuffix(text, "\n"),
+				oldPrevious: oldPrevious,
+				newPrevious: newPrevious,
+			})
+			if chunk.Type() != diff.Add {
+				oldPrevious++
+			}
+			if chunk.Type() != diff.Delete {
+				newPrevious++
+			}
+		}
+	}
+
+	return lines
+}
+
+// diffHunks groups the changed lines of the file patch into hunks, the changes which are separated by at most twice
+// the context lines are in the same hunk
+func diffHunks(chunks []diff.Chunk, contextLines int) []diffHunk {
+	lines := diffLines(chunks)
+
+	var hunks []diffHunk
+	start, last := -1, -1
+	for index, line := range lines {
+		if line.operation == diff.Equal {
+			continue
+		}
+
+		if start != -1 && index-last-1 > 2*contextLines {
+			hunks = append(hunks, newDiffHunk(lines[start:min(last+contextLines+1, len(lines))]))
+			start = -1
+		}
+
+		if start == -1 {
+			start = max(index-contextLines, 0)
+		}
+		last = index
+	}
+
+	if start != -1 {
+		hunks = append(hunks, newDiffHunk(lines[start:min(last+contextLines+1, len(lines))]))
+	}
+
+	return hunks
+}
+
+func newDiffHunk(lines []diffLine) diffHunk {
+	hunk := diffHunk{lines: lines}
+	for _, line := range lines {
+		if line.operation != diff.Add {
+			hunk.oldLines++
+		}
+		if line.operation != diff.Delete {
+			hunk.newLines++
+		}
+	}
+
+	hunk.oldStart = lines[0].oldPrevious
+	if hunk.oldLines > 0 {
+		hunk.oldStart++
+	}
+
+	hunk.newStart = lines[0].newPrevious
+	if hunk.newLines > 0 {
+		hunk.newStart++
+	}
+
+	return hunk
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/stretchr/testify/assert"
+)
+
+type testChunk struct {
+	content   string
+	operation diff.Operation
+}
+
+func (c testChunk) Content() string {
+	return c.content
+}
+
+func (c testChunk) Type() diff.Operation {
+	return c.operation
+}
+
+func numberedLines(first, last int) string {
+	var builder strings.Builder
+	for number := first; number <= last; number++ {
+		builder.WriteString(strings.Repeat("x", number))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func TestDiffHunkHeader(t *testing.T) {
+	hunk := diffHunk{oldStart: 1, oldLines: 3, newStart: 1, newLines: 4}
+	assert.Equal(t, "@@ -1,3 +1,4 @@", hunk.Header())
+
+	hunk = diffHunk{oldStart: 0, oldLines: 0, newStart: 1, newLines: 1}
+	assert.Equal(t, "@@ -0,0 +1 @@", hunk.Header())
+}
+
+func TestHunkRange(t *testing.T) {
+	assert.Equal(t, "5", hunkRange(5, 1))
+	assert.Equal(t, "5,2", hunkRange(5, 2))
+	assert.Equal(t, "0,0", hunkRange(0, 0))
+}
+
+func TestDiffLines(t *testing.T) {
+	chunks := []diff.Chunk{
+		testChunk{"a\n", diff.Equal},
+		testChunk{"b\n", diff.Delete},
+		testChunk{"c\nd", diff.Add},
+	}
+
+	assert.Equal(t, []diffLine{
+		{operation: diff.Equal, text: "a", oldPrevious: 0, newPrevious: 0},
+		{operation: diff.Delete, text: "b", oldPrevious: 1, newPrevious: 1},
+		{operation: diff.Add, text: "c", oldPrevious: 2, newPrevious: 1},
+		{operation: diff.Add, text: "d", oldPrevious: 2, newPrevious: 2},
+	}, diffLines(chunks))
+}
+
+func TestDiffHunks(t *testing.T) {
+	// Added file
+	hunks := diffHunks([]diff.Chunk{testChunk{"a\nb\n", diff.Add}}, hunkContextLines)
+	assert.Equal(t, 1, len(hunks))
+	assert.Equal(t, "@@ -0,0 +1,2 @@", hunks[0].Header())
+
+	// Changes separated by more than twice the context lines
+	chunks := []diff.Chunk{
+		testChunk{numberedLines(1, 5), diff.Equal},
+		testChunk{"old\n", diff.Delete},
+		testChunk{"new\n", diff.Add},
+		testChunk{numberedLines(7, 13), diff.Equal},
+		testChunk{"more\n", diff.Add},
+		testChunk{numberedLines(14, 14), diff.Equal},
+	}
+
+	hunks = diffHunks(chunks, hunkContextLines)
+	assert.Equal(t, 2, len(hunks))
+	assert.Equal(t, "@@ -3,7 +3,7 @@", hunks[0].Header())
+	assert.Equal(t, 8, len(hunks[0].lines))
+	assert.Equal(t, "@@ -11,4 +11,5 @@", hunks[1].Header())
+
+	// Changes separated by twice the context lines are in the same hunk
+	chunks[3] = testChunk{numberedLines(7, 12), diff.Equal}
+
+	hunks = diffHunks(chunks, hunkContextLines)
+	assert.Equal(t, 1, len(hunks))
+	assert.Equal(t, "@@ -3,11 +3,12 @@", hunks[0].Header())
+
+	// No changes
+	hunks = diffHunks([]diff.Chunk{testChunk{"a\n", diff.Equal}}, hunkContextLines)
+	assert.Equal(t, 0, len(hunks))
+}
+
+func TestNewDiffHunk(t *testing.T) {
+	hunk := newDiffHunk([]diffLine{
+		{operation: diff.Equal, text: "a", oldPrevious: 4, newPrevious: 6},
+		{operation: diff.Delete, text: "b", oldPrevious: 5, newPrevious: 7},
+	})
+	assert.Equal(t, 5, hunk.oldStart)
+	assert.Equal(t, 2, hunk.oldLines)
+	assert.Equal(t, 7, hunk.newStart)
+	assert.Equal(t, 1, hunk.newLines)
+
+	hunk = newDiffHunk([]diffLine{{operation: diff.Delete, text: "a", oldPrevious: 0, newPrevious: 0}})
+	assert.Equal(t, 1, hunk.oldStart)
+	assert.Equal(t, 0, hunk.newStart)
+	assert.Equal(t, 0, hunk.newLines)
+}
@@ -68,6 +68,9 @@ func EvaluateExpression(env *ast.Environment, expression ast.Expression, titles
 		return EvaluateIsNull(env, expr, titles, object)
 	case ast.ExprNull:
 		return ast.NullValue{}, nil
+	case ast.ExprSubquery:
+		expr := expression.(*ast.SubqueryExpression)
+		return EvaluateSubquery(expr)
 	default:
 		return nil, errors.New("invalid expression kind")
 	}
@@ -198,6 +201,11 @@ func EvaluateComparison(env *ast.Environment, expr *ast.ComparisonExpression, ti
 		}
 	}
 
+	// NULL is never equal, less or greater than another value
+	if lhs.DataType().IsNull() || rhs.DataType().IsNull() {
+		return ast.BooleanValue{Value: false}, nil
+	}
+
 	switch expr.Operator {
 	case ast.COGreater:
 		return ast.BooleanValue{Value: comparisonResult == ast.Greater}, nil
@@ -363,6 +371,10 @@ func EvaluateBetween(env *ast.Environment, expr *ast.BetweenExpression, titles [
 		return nil, err
 	}
 
+	if value.DataType().IsNull() {
+		return ast.BooleanValue{Value: false}, nil
+	}
+
 	retStart := value.Compare(rangeStart) == ast.Less || value.Compare(rangeStart) == ast.Equal
 	retEnd := value.Compare(rangeEnd) == ast.Greater || value.Compare(rangeEnd) == ast.Equal
 
@@ -396,6 +408,22 @@ func EvaluateIn(env *ast.Environment, expr *ast.InExpression, titles []string, o
 		return nil, err
 	}
 
+	if argument.DataType().IsNull() {
+		return ast.BooleanValue{Value: false}, nil
+	}
+
+	if expr.Subquery != nil {
+		if !expr.Subquery.IsEvaluated {
+			return nil, errors.New("subquery is not evaluated")
+		}
+		for _, value := range expr.Subquery.Values {
+			if argument.Equals(value) {
+				return ast.BooleanValue{Value: !expr.HasNotKeyword}, nil
+			}
+		}
+		return ast.BooleanValue{Value: expr.HasNotKeyword}, nil
+	}
+
 	for _, valueExpr := range expr.Values {
 		value, err := EvaluateExpression(env, valueExpr, titles, object)
 		if err != nil {
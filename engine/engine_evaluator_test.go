@@ -501,6 +501,31 @@ func TestEvaluateComparison(t *testing.T) {
 	value, err = EvaluateComparison(&env, &comparisonExpression, titles, object)
 	assert.Equal(t, nil, err)
 	assert.Equal(t, false, value.AsBool())
+
+	// NULL is never equal, less or greater than a value
+	for _, operator := range []ast.ComparisonOperator{ast.COEqual, ast.CONotEqual, ast.COLess, ast.COGreaterEqual} {
+		comparisonExpression = ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "title"},
+			Operator: operator,
+			Right:    &ast.StringExpression{Value: "object", ValueType: ast.StringValueText},
+		}
+
+		value, err = EvaluateComparison(&env, &comparisonExpression, titles, []ast.Value{ast.NullValue{}})
+		assert.Equal(t, nil, err)
+		assert.Equal(t, false, value.AsBool())
+	}
+
+	// Booleans are compared by their values
+	comparisonExpression = ast.ComparisonExpression{
+		Left:     &ast.BooleanExpression{IsTrue: true},
+		Operator: ast.COEqual,
+		Right:    &ast.BooleanExpression{IsTrue: false},
+	}
+
+	value, err = EvaluateComparison(&env, &comparisonExpression, titles, object)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, value.AsBool())
+
 }
 
 func TestEvaluateLike(t *testing.T) {
@@ -844,6 +869,13 @@ func TestEvaluateBetween(t *testing.T) {
 	value, err = EvaluateBetween(&env, &betweenExpression, titles, object)
 	assert.Equal(t, nil, err)
 	assert.Equal(t, false, value.AsBool())
+
+	// NULL is never between values
+	betweenExpression.Value = &ast.NullExpression{}
+
+	value, err = EvaluateBetween(&env, &betweenExpression, titles, object)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, value.AsBool())
 }
 
 func TestEvaluateCase(t *testing.T) {
@@ -924,6 +956,31 @@ func TestEvaluateIn(t *testing.T) {
 	value, err := EvaluateIn(&env, &inExpression, titles, object)
 	assert.Equal(t, nil, err)
 	assert.Equal(t, true, value.AsBool())
+
+	// Values selected by a subquery
+	subquery := &ast.SubqueryExpression{
+		ValueType: ast.Text{},
+		Values:    []ast.Value{ast.TextValue{Value: "Two"}},
+	}
+	inExpression.Values = nil
+	inExpression.Subquery = subquery
+
+	_, err = EvaluateIn(&env, &inExpression, titles, object)
+	assert.Equal(t, "subquery is not evaluated", err.Error())
+
+	subquery.IsEvaluated = true
+	inExpression.HasNotKeyword = true
+
+	value, err = EvaluateIn(&env, &inExpression, titles, object)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, value.AsBool())
+
+	// NULL is neither in nor not in the values
+	inExpression.Argument = &ast.NullExpression{}
+
+	value, err = EvaluateIn(&env, &inExpression, titles, object)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, value.AsBool())
 }
 
 func TestEvaluateIsNull(t *testing.T) {
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/go-git/go-git/v5"
@@ -20,8 +21,8 @@ func ExecuteStatement(
 	switch statement.Kind() {
 	case ast.Select:
 		selectStatement := statement.(*ast.SelectStatement)
-		for _, alias := range selectStatement.AliasTable {
-			aliasTable[alias] = alias
+		for fieldName, alias := range selectStatement.AliasTable {
+			aliasTable[fieldName] = alias
 		}
 		return executeSelectStatement(env, selectStatement, repo, gitqlObject, hiddenSelection)
 	case ast.Where:
@@ -41,10 +42,13 @@ func ExecuteStatement(
 		return executeOrderByStatement(env, orderByStatement, gitqlObject)
 	case ast.GroupBy:
 		groupByStatement := statement.(*ast.GroupByStatement)
-		return executeGroupByStatement(groupByStatement, gitqlObject)
+		return executeGroupByStatement(env, groupByStatement, gitqlObject)
 	case ast.AggregateFunction:
 		aggregationsStatement := statement.(*ast.AggregationsStatement)
 		return executeAggregationFunctionStatement(env, aggregationsStatement, gitqlObject, aliasTable)
+	case ast.WindowFunction:
+		windowFunctionsStatement := statement.(*ast.WindowFunctionsStatement)
+		return executeWindowFunctionsStatement(env, windowFunctionsStatement, gitqlObject, aliasTable)
 	case ast.GlobalVariable:
 		globalVariableStatement := statement.(*ast.GlobalVariableStatement)
 		return executeGlobalVariableStatement(env, globalVariableStatement)
@@ -60,24 +64,96 @@ func executeSelectStatement(
 	gitqlObject *ast.GitQLObject,
 	hiddenSelections []string,
 ) error {
-	fieldsNames := statement.FieldsNames
-	if statement.TableName != "" {
-		for _, hidden := range hiddenSelections {
-			if !contains(fieldsNames, hidden) {
-				fieldsNames = append(fieldsNames, hidden)
-			}
+	if statement.TableName == "" {
+		if len(gitqlObject.Titles) == 0 {
+			gitqlObject.Titles = append(gitqlObject.Titles, statement.FieldsNames...)
+		}
+
+		objects, err := SelectGQLObjects(env, repo, statement.TableName, statement.FieldsNames, gitqlObject.Titles, statement.FieldsValues, statement.Condition)
+		if err != nil {
+			return err
+		}
+
+		if gitqlObject.IsEmpty() {
+			gitqlObject.Groups = append(gitqlObject.Groups, *objects)
+		} else {
+			gitqlObject.Groups[0].Rows = append(gitqlObject.Groups[0].Rows, objects.Rows...)
+		}
+
+		return nil
+	}
+
+	if len(statement.Joins) != 0 {
+		return executeJoinSelectStatement(env, statement, repo, gitqlObject, hiddenSelections)
+	}
+
+	// Hidden selections are selected first then the selected symbols, so the
+	// selected expressions can use the values of any field
+	var fieldsNames []string
+	for _, hidden := range hiddenSelections {
+		if !contains(statement.FieldsNames, hidden) {
+			fieldsNames = append(fieldsNames, hidden)
+		}
+	}
+
+	hiddenCount := len(fieldsNames)
+
+	selectedValues := make([]ast.Expression, len(statement.FieldsNames))
+	for index, fieldName := range statement.FieldsNames {
+		if index < len(statement.FieldsValues) {
+			selectedValues[index] = statement.FieldsValues[index]
+		} else {
+			selectedValues[index] = &ast.SymbolExpression{Value: fieldName}
 		}
 	}
 
-	for _, fieldName := range fieldsNames {
-		gitqlObject.Titles = append(gitqlObject.Titles, GetColumnName(statement.AliasTable, fieldName))
+	order := make([]int, 0, len(selectedValues))
+	for index, value := range selectedValues {
+		if _, ok := value.(*ast.SymbolExpression); ok {
+			order = append(order, index)
+		}
 	}
+	for index, value := range selectedValues {
+		if _, ok := value.(*ast.SymbolExpression); !ok {
+			order = append(order, index)
+		}
+	}
+
+	fieldsValues := make([]ast.Expression, 0, len(order))
+	for _, index := range order {
+		fieldsNames = append(fieldsNames, statement.FieldsNames[index])
+		fieldsValues = append(fieldsValues, selectedValues[index])
+	}
+
+	titles := make([]string, 0, len(fieldsNames))
+	titles = append(titles, fieldsNames...)
 
-	objects, err := SelectGQLObjects(env, repo, statement.TableName, fieldsNames, gitqlObject.Titles, statement.FieldsValues)
+	var objects *ast.Group
+	var err error
+	if statement.DerivedTable != nil {
+		objects, err = selectDerivedTable(env, repo, statement.DerivedTable, fieldsNames, titles, fieldsValues)
+	} else {
+		objects, err = SelectGQLObjects(env, repo, statement.TableName, fieldsNames, titles, fieldsValues, statement.Condition)
+	}
 	if err != nil {
 		return err
 	}
 
+	// Restore the selected fields order
+	for _, row := range objects.Rows {
+		values := make([]ast.Value, len(row.Values))
+		copy(values, row.Values[:hiddenCount])
+		for position, index := range order {
+			values[hiddenCount+index] = row.Values[hiddenCount+position]
+		}
+		copy(row.Values, values)
+	}
+
+	if len(gitqlObject.Titles) == 0 {
+		gitqlObject.Titles = append(gitqlObject.Titles, titles[:hiddenCount]...)
+		gitqlObject.Titles = append(gitqlObject.Titles, statement.FieldsNames...)
+	}
+
 	if gitqlObject.IsEmpty() {
 		gitqlObject.Groups = append(gitqlObject.Groups, *objects)
 	} else {
@@ -244,6 +320,7 @@ func executeOrderByStatement(
 }
 
 func executeGroupByStatement(
+	env *ast.Environment,
 	statement *ast.GroupByStatement,
 	gitqlObject *ast.GitQLObject,
 ) error {
@@ -257,22 +334,30 @@ func executeGroupByStatement(
 	}
 
 	groupsMap := make(map[string]int)
-	nextGroupIndex := 0
+	groups := make([]ast.Group, 0)
 
 	for _, object := range mainGroup.Rows {
-		fieldIndex := indexOf(gitqlObject.Titles, statement.FieldName)
-		fieldValue := object.Values[fieldIndex].Fmt()
-		if _, ok := groupsMap[fieldValue]; !ok {
-			groupsMap[fieldValue] = nextGroupIndex
-			nextGroupIndex++
-			gitqlObject.Groups = append(gitqlObject.Groups, ast.Group{Rows: []ast.Row{object}})
+		// The group key is composed of the values of all the group by expressions
+		values := make([]ast.Value, 0, len(statement.Values))
+		for _, expression := range statement.Values {
+			value, err := EvaluateExpression(env, expression, gitqlObject.Titles, object.Values)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+
+		key := rowKey(values)
+		if index, ok := groupsMap[key]; ok {
+			groups[index].Rows = append(groups[index].Rows, object)
 		} else {
-			index := groupsMap[fieldValue]
-			targetGroup := &gitqlObject.Groups[index]
-			targetGroup.Rows = append(targetGroup.Rows, object)
+			groupsMap[key] = len(groups)
+			groups = append(groups, ast.Group{Rows: []ast.Row{object}})
 		}
 	}
 
+	gitqlObject.Groups = groups
+
 	return nil
 }
 
@@ -286,58 +371,73 @@ func executeAggregationFunctionStatement(
 		return nil
 	}
 
-	groupsCount := gitqlObject.Len()
+	// Aggregations expressions can use the results of other aggregations, so they are evaluated in the same order as
+	// their generated columns
+	var functionsColumns []string
+	var expressionsColumns []string
+	for columnName, aggregation := range statement.Aggregations {
+		if aggregation.Function.Name != "" && aggregation.Function.Arg != "" {
+			functionsColumns = append(functionsColumns, columnName)
+		}
+		if aggregation.Expression != nil {
+			expressionsColumns = append(expressionsColumns, columnName)
+		}
+	}
+	sortGeneratedColumnsNames(expressionsColumns)
 
-	for _, group := range gitqlObject.Groups {
+	for index := range gitqlObject.Groups {
+		group := &gitqlObject.Groups[index]
 		if group.IsEmpty() {
 			continue
 		}
+
 		// Resolve all aggregations functions first
-		for _, aggregation := range statement.Aggregations {
-			fn := aggregation.Function
-			if fn.Name != "" && fn.Arg != "" {
-				// Get alias name if exists or column name by default
-				resultColumnName := aggregation.Function.Arg
-				columnName := GetColumnName(aliasTable, resultColumnName)
-				columnIndex := indexOf(gitqlObject.Titles, columnName)
-				aggregationFunction := ast.Aggregations[fn.Name]
-				var g ast.Group
-				g = group
-				result := aggregationFunction(fn.Arg, gitqlObject.Titles, &g)
-				for _, object := range group.Rows {
-					if columnIndex < len(object.Values) {
-						object.Values[columnIndex] = result
-					} else {
-						object.Values = append(object.Values, result)
-					}
-				}
+		for _, resultColumnName := range functionsColumns {
+			function := statement.Aggregations[resultColumnName].Function
+			// Get alias name if exists or column name by default
+			columnIndex := indexOf(gitqlObject.Titles, GetColumnName(aliasTable, resultColumnName))
+			if columnIndex == -1 {
+				return fmt.Errorf("invalid aggregation column name %s", resultColumnName)
+			}
+
+			aggregationFunction := ast.Aggregations[function.Name]
+			result := aggregationFunction(GetColumnName(aliasTable, function.Arg), gitqlObject.Titles, group)
+			for _, object := range group.Rows {
+				object.Values[columnIndex] = result
 			}
 		}
+
 		// Resolve aggregations expressions
-		for _, aggregation := range statement.Aggregations {
-			if expr := aggregation.Expression; expr != nil {
-				resultColumnName := aggregation.Function.Arg
-				columnName := GetColumnName(aliasTable, resultColumnName)
-				columnIndex := indexOf(gitqlObject.Titles, columnName)
-
-				for _, object := range group.Rows {
-					result, _ := EvaluateExpression(env, expr, gitqlObject.Titles, object.Values)
-					if columnIndex < len(object.Values) {
-						object.Values[columnIndex] = result
-					} else {
-						object.Values = append(object.Values, result)
-					}
+		for _, resultColumnName := range expressionsColumns {
+			expression := statement.Aggregations[resultColumnName].Expression
+			columnIndex := indexOf(gitqlObject.Titles, GetColumnName(aliasTable, resultColumnName))
+			if columnIndex == -1 {
+				return fmt.Errorf("invalid aggregation column name %s", resultColumnName)
+			}
+
+			for _, object := range group.Rows {
+				result, err := EvaluateExpression(env, expression, gitqlObject.Titles, object.Values)
+				if err != nil {
+					return err
 				}
+				object.Values[columnIndex] = result
 			}
 		}
-		if groupsCount > 1 {
-			group.Rows = group.Rows[:1]
-		}
 	}
 
 	return nil
 }
 
+// sortGeneratedColumnsNames sorts the names like `column_2` and `column_10` by their generation order
+func sortGeneratedColumnsNames(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+}
+
 func executeGlobalVariableStatement(
 	env *ast.Environment,
 	statement *ast.GlobalVariableStatement,
@@ -369,34 +469,31 @@ func indexOf(slice []string, item string) int {
 	return -1
 }
 
-func compare(a, b interface{}) int {
-	switch a := a.(type) {
-	case int:
-		b := b.(int)
-		if a < b {
-			return -1
-		} else if a > b {
-			return 1
-		} else {
+// compare returns the ordering of the first value relative to the other value, null values are sorted before the
+// other values and Integer values are comparable with Float values
+func compare(first, other ast.Value) int {
+	firstType, otherType := first.DataType(), other.DataType()
+	if firstType.IsNull() || otherType.IsNull() {
+		switch {
+		case firstType.IsNull() && otherType.IsNull():
 			return 0
-		}
-	case float64:
-		b := b.(float64)
-		if a < b {
+		case firstType.IsNull():
 			return -1
-		} else if a > b {
+		default:
 			return 1
-		} else {
-			return 0
 		}
-	case string:
-		b := b.(string)
-		if sort.StringsAreSorted([]string{a, b}) {
+	}
+
+	if firstType.IsNumber() && otherType.IsNumber() && (firstType.IsFloat() || otherType.IsFloat()) {
+		firstNumber, otherNumber := first.AsFloat(), other.AsFloat()
+		if firstNumber < otherNumber {
+			return -1
+		} else if firstNumber > otherNumber {
 			return 1
-		} else {
-			return 0
 		}
-	default:
 		return 0
 	}
+
+	// The value comparison returns the ordering of the other value relative to the value
+	return int(other.Compare(first))
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/ggql/ggql/ast"
 )
@@ -84,6 +85,35 @@ func TestExecuteSelectStatement(t *testing.T) {
 	} else {
 		t.Errorf("execute statement failed: %v", ret)
 	}
+
+	// Test: SELECT concat("title: ", title), title FROM commits
+	env.Define("title", ast.Text{})
+	statement = &ast.SelectStatement{
+		TableName:   "commits",
+		FieldsNames: []string{"column_1", "title"},
+		FieldsValues: []ast.Expression{
+			&ast.CallExpression{
+				FunctionName: "concat",
+				Arguments: []ast.Expression{
+					&ast.StringExpression{Value: "title: ", ValueType: ast.StringValueText},
+					&ast.SymbolExpression{Value: "title"},
+				},
+			},
+			&ast.SymbolExpression{Value: "title"},
+		},
+		AliasTable: make(map[string]string),
+	}
+
+	commitsRepo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	object = ast.GitQLObject{}
+	ret = executeSelectStatement(&env, statement, commitsRepo, &object, []string{"commit_id"})
+	assert.Equal(t, nil, ret)
+	assert.Equal(t, []string{"commit_id", "column_1", "title"}, object.Titles)
+
+	values := object.Groups[0].Rows[0].Values
+	assert.Equal(t, "title: "+values[2].AsText(), values[1].AsText())
 }
 
 func TestExecuteWhereStatement(t *testing.T) {
@@ -222,11 +252,37 @@ func TestExecuteOrderByStatement(t *testing.T) {
 	} else {
 		t.Errorf("execute statement failed: %v", ret)
 	}
+
+	// Order by a field descending
+	statement = &ast.OrderByStatement{
+		Arguments:     []ast.Expression{&ast.SymbolExpression{Value: fieldTitle1}},
+		SortingOrders: []ast.SortingOrder{ast.Descending},
+	}
+	ret = executeOrderByStatement(&env, statement, &object)
+	assert.Nil(t, ret)
+	assert.Equal(t, ast.IntegerValue{Value: 3}, object.Groups[0].Rows[0].Values[0])
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, -1, compare(ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 2}))
+	assert.Equal(t, 1, compare(ast.TextValue{Value: "b"}, ast.TextValue{Value: "a"}))
+	assert.Equal(t, 0, compare(ast.TextValue{Value: "a"}, ast.TextValue{Value: "a"}))
+	assert.Equal(t, 1, compare(ast.FloatValue{Value: 2.5}, ast.IntegerValue{Value: 2}))
+
+	// Null values are sorted first
+	assert.Equal(t, -1, compare(ast.NullValue{}, ast.IntegerValue{Value: 1}))
+	assert.Equal(t, 1, compare(ast.IntegerValue{Value: 1}, ast.NullValue{}))
+	assert.Equal(t, 0, compare(ast.NullValue{}, ast.NullValue{}))
 }
 
 func TestExecuteGroupByStatement(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
 	statement := &ast.GroupByStatement{
-		FieldName: fieldTitle1,
+		Values: []ast.Expression{&ast.SymbolExpression{Value: fieldTitle1}},
 	}
 	var object ast.GitQLObject
 	object.Titles = []string{fieldTitle1, fieldTitle2}
@@ -240,12 +296,46 @@ func TestExecuteGroupByStatement(t *testing.T) {
 			}},
 		}},
 	}
-	ret := executeGroupByStatement(statement, &object)
+	ret := executeGroupByStatement(&env, statement, &object)
 	if ret == nil {
 		t.Log("execute statement succeeded")
 	} else {
 		t.Errorf("execute statement failed: %v", ret)
 	}
+
+	// Group by several expressions
+	statement = &ast.GroupByStatement{
+		Values: []ast.Expression{
+			&ast.SymbolExpression{Value: fieldTitle1},
+			&ast.ArithmeticExpression{
+				Left:     &ast.SymbolExpression{Value: fieldTitle2},
+				Operator: ast.AOModulus,
+				Right:    &ast.NumberExpression{Value: ast.IntegerValue{Value: 2}},
+			},
+		},
+	}
+	object.Titles = []string{fieldTitle1, fieldTitle2}
+	object.Groups = []ast.Group{
+		{Rows: []ast.Row{
+			{Values: []ast.Value{ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 2}}},
+			{Values: []ast.Value{ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 3}}},
+			{Values: []ast.Value{ast.IntegerValue{Value: 2}, ast.IntegerValue{Value: 2}}},
+			{Values: []ast.Value{ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 4}}},
+		}},
+	}
+
+	ret = executeGroupByStatement(&env, statement, &object)
+	assert.Nil(t, ret)
+	assert.Equal(t, 3, len(object.Groups))
+	assert.Equal(t, 2, len(object.Groups[0].Rows))
+	assert.Equal(t, ast.IntegerValue{Value: 4}, object.Groups[0].Rows[1].Values[1])
+	assert.Equal(t, 1, len(object.Groups[1].Rows))
+	assert.Equal(t, 1, len(object.Groups[2].Rows))
+
+	// Unknown fields can't be evaluated
+	statement = &ast.GroupByStatement{Values: []ast.Expression{&ast.SymbolExpression{Value: "missing"}}}
+	ret = executeGroupByStatement(&env, statement, &object)
+	assert.NotNil(t, ret)
 }
 
 func TestExecuteAggregationFunctionStatement(t *testing.T) {
@@ -283,6 +373,49 @@ func TestExecuteAggregationFunctionStatement(t *testing.T) {
 	} else {
 		t.Errorf("execute statement failed: %v", ret)
 	}
+
+	// The results are stored in the generated columns of each group, the expressions use the functions results
+	statement = &ast.AggregationsStatement{Aggregations: make(map[string]ast.AggregateValue)}
+	var maxValue ast.AggregateValue
+	maxValue.Function.Name = "max"
+	maxValue.Function.Arg = fieldTitle1
+	statement.Aggregations["column_1"] = maxValue
+	statement.Aggregations["column_2"] = ast.AggregateValue{
+		Expression: &ast.ArithmeticExpression{
+			Left:     &ast.SymbolExpression{Value: "m"},
+			Operator: ast.AOPlus,
+			Right:    &ast.NumberExpression{Value: ast.IntegerValue{Value: 1}},
+		},
+	}
+
+	object.Titles = []string{fieldTitle1, "m", "column_2"}
+	object.Groups = []ast.Group{
+		{Rows: []ast.Row{
+			{Values: []ast.Value{ast.IntegerValue{Value: 1}, ast.NullValue{}, ast.NullValue{}}},
+			{Values: []ast.Value{ast.IntegerValue{Value: 3}, ast.NullValue{}, ast.NullValue{}}},
+		}},
+		{Rows: []ast.Row{
+			{Values: []ast.Value{ast.IntegerValue{Value: 5}, ast.NullValue{}, ast.NullValue{}}},
+		}},
+	}
+
+	ret = executeAggregationFunctionStatement(&env, statement, &object, map[string]string{"column_1": "m"})
+	assert.Nil(t, ret)
+	assert.Equal(t, ast.IntegerValue{Value: 3}, object.Groups[0].Rows[0].Values[1])
+	assert.Equal(t, ast.IntegerValue{Value: 3}, object.Groups[0].Rows[1].Values[1])
+	assert.Equal(t, int64(4), object.Groups[0].Rows[0].Values[2].AsInt())
+	assert.Equal(t, ast.IntegerValue{Value: 5}, object.Groups[1].Rows[0].Values[1])
+	assert.Equal(t, int64(6), object.Groups[1].Rows[0].Values[2].AsInt())
+
+	// The generated column must be selected
+	ret = executeAggregationFunctionStatement(&env, statement, &object, map[string]string{})
+	assert.NotNil(t, ret)
+}
+
+func TestSortGeneratedColumnsNames(t *testing.T) {
+	names := []string{"column_10", "column_2", "column_1"}
+	sortGeneratedColumnsNames(names)
+	assert.Equal(t, []string{"column_1", "column_2", "column_10"}, names)
 }
 
 func TestExecuteGlobalVariableStatement(t *testing.T) {
@@ -0,0 +1,130 @@
+package engine
+
+import (
+	"github.com/ggql/ggql/ast"
+)
+
+// tableFilter is the conditions of the `WHERE` statement which must be true for all the selected rows, the tables
+// which are expensive to select use it to skip the rows before computing their fields, the `WHERE` statement is still
+// executed on the selected rows
+type tableFilter struct {
+	env        *ast.Environment
+	conditions []ast.Expression
+	prefix     string
+}
+
+func newTableFilter(env *ast.Environment, condition ast.Expression) tableFilter {
+	filter := tableFilter{env: env}
+	filter.addConditions(condition)
+	return filter
+}
+
+// newJoinedTableFilter returns the filter of a joined table, the fields of the join query conditions are qualified
+// with the table reference like `reference.field`
+func newJoinedTableFilter(env *ast.Environment, condition ast.Expression, reference string) tableFilter {
+	filter := newTableFilter(env, condition)
+	filter.prefix = reference + "."
+	return filter
+}
+
+// addConditions splits the `AND` operands, each operand must be true for the selected rows
+func (f *tableFilter) addConditions(condition ast.Expression) {
+	if condition == nil {
+		return
+	}
+
+	if logical, ok := condition.(*ast.LogicalExpression); ok && logical.Operator == ast.LOAnd {
+		f.addConditions(logical.Left)
+		f.addConditions(logical.Right)
+		return
+	}
+
+	f.conditions = append(f.conditions, condition)
+}
+
+// Value returns the value of the field if the filter has a condition like `field = value`
+func (f *tableFilter) Value(fieldName string) (ast.Value, bool) {
+	fieldName = f.prefix + fieldName
+	for _, condition := range f.conditions {
+		comparison, ok := condition.(*ast.ComparisonExpression)
+		if !ok || comparison.Operator != ast.COEqual {
+			continue
+		}
+
+		value := comparison.Right
+		if !isFieldExpression(comparison.Left, fieldName) {
+			value = comparison.Left
+			if !isFieldExpression(comparison.Right, fieldName) {
+				continue
+			}
+		}
+
+		if !isConstantExpression(value) {
+			continue
+		}
+
+		evaluated, err := EvaluateExpression(f.env, value, nil, nil)
+		if err == nil {
+			return evaluated, true
+		}
+	}
+	return nil, false
+}
+
+// Matches returns false if the field value doesn't match a condition which only uses this field
+func (f *tableFilter) Matches(fieldName string, value ast.Value) bool {
+	fieldName = f.prefix + fieldName
+	titles := []string{fieldName}
+	values := []ast.Value{value}
+
+	for _, condition := range f.conditions {
+		if !isFieldCondition(condition, fieldName) {
+			continue
+		}
+
+		isMatch, err := EvaluateExpression(f.env, condition, titles, values)
+		if err == nil && !isMatch.AsBool() {
+			return false
+		}
+	}
+	return true
+}
+
+// isFieldCondition returns true if the condition compares the field with constant values
+func isFieldCondition(condition ast.Expression, fieldName string) bool {
+	switch expression := condition.(type) {
+	case *ast.ComparisonExpression:
+		return (isFieldExpression(expression.Left, fieldName) && isConstantExpression(expression.Right)) ||
+			(isConstantExpression(expression.Left) && isFieldExpression(expression.Right, fieldName))
+	case *ast.LikeExpression:
+		return isFieldExpression(expression.Input, fieldName) && isConstantExpression(expression.Pattern)
+	case *ast.GlobExpression:
+		return isFieldExpression(expression.Input, fieldName) && isConstantExpression(expression.Pattern)
+	case *ast.InExpression:
+		if !isFieldExpression(expression.Argument, fieldName) || expression.Subquery != nil {
+			return false
+		}
+		for _, value := range expression.Values {
+			if !isConstantExpression(value) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isFieldExpression(expression ast.Expression, fieldName string) bool {
+	symbol, ok := expression.(*ast.SymbolExpression)
+	return ok && symbol.Value == fieldName
+}
+
+func isConstantExpression(expression ast.Expression) bool {
+	switch expression.(type) {
+	case *ast.StringExpression, *ast.NumberExpression, *ast.BooleanExpression, *ast.NullExpression:
+		return true
+	default:
+		return false
+	}
+}
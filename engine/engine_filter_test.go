@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+)
+
+func newFilterCondition() ast.Expression {
+	// revision = "v1" AND path LIKE "pkg/%" AND (path = "a" OR line_no = 1)
+	return &ast.LogicalExpression{
+		Left: &ast.LogicalExpression{
+			Left: &ast.ComparisonExpression{
+				Left:     &ast.SymbolExpression{Value: "revision"},
+				Operator: ast.COEqual,
+				Right:    &ast.StringExpression{Value: "v1", ValueType: ast.StringValueText},
+			},
+			Operator: ast.LOAnd,
+			Right: &ast.LikeExpression{
+				Input:   &ast.SymbolExpression{Value: "path"},
+				Pattern: &ast.StringExpression{Value: "pkg/%", ValueType: ast.StringValueText},
+			},
+		},
+		Operator: ast.LOAnd,
+		Right: &ast.LogicalExpression{
+			Left: &ast.ComparisonExpression{
+				Left:     &ast.SymbolExpression{Value: "path"},
+				Operator: ast.COEqual,
+				Right:    &ast.StringExpression{Value: "a", ValueType: ast.StringValueText},
+			},
+			Operator: ast.LOOr,
+			Right: &ast.ComparisonExpression{
+				Left:     &ast.SymbolExpression{Value: "line_no"},
+				Operator: ast.COEqual,
+				Right:    &ast.NumberExpression{Value: ast.IntegerValue{Value: 1}},
+			},
+		},
+	}
+}
+
+func TestNewTableFilter(t *testing.T) {
+	env := newMutationEnv()
+
+	filter := newTableFilter(&env, newFilterCondition())
+	assert.Equal(t, 3, len(filter.conditions))
+
+	filter = newTableFilter(&env, nil)
+	assert.Equal(t, 0, len(filter.conditions))
+}
+
+func TestNewJoinedTableFilter(t *testing.T) {
+	env := newMutationEnv()
+
+	// f.revision = "v1" AND path = "a"
+	condition := &ast.LogicalExpression{
+		Left: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "f.revision"},
+			Operator: ast.COEqual,
+			Right:    &ast.StringExpression{Value: "v1", ValueType: ast.StringValueText},
+		},
+		Operator: ast.LOAnd,
+		Right: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "path"},
+			Operator: ast.COEqual,
+			Right:    &ast.StringExpression{Value: "a", ValueType: ast.StringValueText},
+		},
+	}
+
+	filter := newJoinedTableFilter(&env, condition, "f")
+	value, ok := filter.Value("revision")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, ast.TextValue{Value: "v1"}, value)
+
+	// The fields which are not qualified with the table reference are not filtered
+	_, ok = filter.Value("path")
+	assert.Equal(t, false, ok)
+	assert.Equal(t, true, filter.Matches("path", ast.TextValue{Value: "b"}))
+	assert.Equal(t, false, filter.Matches("revision", ast.TextValue{Value: "v2"}))
+}
+
+func TestTableFilterValue(t *testing.T) {
+	env := newMutationEnv()
+	filter := newTableFilter(&env, newFilterCondition())
+
+	value, ok := filter.Value("revision")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, ast.TextValue{Value: "v1"}, value)
+
+	// The path is compared in an `OR` condition
+	_, ok = filter.Value("path")
+	assert.Equal(t, false, ok)
+}
+
+func TestTableFilterMatches(t *testing.T) {
+	env := newMutationEnv()
+	filter := newTableFilter(&env, newFilterCondition())
+
+	assert.Equal(t, true, filter.Matches("path", ast.TextValue{Value: "pkg/main.go"}))
+	assert.Equal(t, false, filter.Matches("path", ast.TextValue{Value: "cmd/main.go"}))
+
+	// Fields without conditions match all values
+	assert.Equal(t, true, filter.Matches("name", ast.TextValue{Value: "name"}))
+}
+
+func TestIsFieldCondition(t *testing.T) {
+	path := &ast.SymbolExpression{Value: "path"}
+	text := &ast.StringExpression{Value: "a", ValueType: ast.StringValueText}
+
+	assert.Equal(t, true, isFieldCondition(&ast.ComparisonExpression{Left: text, Operator: ast.COEqual, Right: path}, "path"))
+	assert.Equal(t, false, isFieldCondition(&ast.ComparisonExpression{Left: path, Operator: ast.COEqual, Right: path}, "path"))
+	assert.Equal(t, true, isFieldCondition(&ast.GlobExpression{Input: path, Pattern: text}, "path"))
+	assert.Equal(t, true, isFieldCondition(&ast.InExpression{Argument: path, Values: []ast.Expression{text}}, "path"))
+	assert.Equal(t, false, isFieldCondition(&ast.InExpression{Argument: path, Values: []ast.Expression{path}}, "path"))
+	assert.Equal(t, false, isFieldCondition(&ast.IsNullExpression{Argument: path}, "path"))
+}
+
+func TestIsFieldExpression(t *testing.T) {
+	assert.Equal(t, true, isFieldExpression(&ast.SymbolExpression{Value: "path"}, "path"))
+	assert.Equal(t, false, isFieldExpression(&ast.SymbolExpression{Value: "line"}, "path"))
+	assert.Equal(t, false, isFieldExpression(&ast.NullExpression{}, "path"))
+}
+
+func TestIsConstantExpression(t *testing.T) {
+	assert.Equal(t, true, isConstantExpression(&ast.NumberExpression{Value: ast.IntegerValue{Value: 1}}))
+	assert.Equal(t, true, isConstantExpression(&ast.BooleanExpression{IsTrue: true}))
+	assert.Equal(t, false, isConstantExpression(&ast.SymbolExpression{Value: "path"}))
+}
@@ -1,11 +1,19 @@
 package engine
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/filesystem"
 
@@ -13,13 +21,19 @@ import (
 )
 
 const (
-	changeIdPrefix   = "Change-Id:"
-	messageDelimiter = "\n"
+	changeIdKey    = "Change-Id"
+	gitModulesFile = ".gitmodules"
+)
+
+// The flags of the commits walked by aheadBehind
+const (
+	reachableFromLocal = 1 << iota
+	reachableFromUpstream
 )
 
 var (
-	changeIdPattern    = regexp.MustCompile("(" + changeIdPrefix + " *I[a-f0-9]{40})")
-	trailingWhitespace = regexp.MustCompile("\\s+$")
+	changeIdValuePattern = regexp.MustCompile("^I[a-f0-9]{40}$")
+	errUnknownRevision   = errors.New("unknown revision")
 )
 
 func SelectGQLObjects(
@@ -29,6 +43,20 @@ func SelectGQLObjects(
 	fieldsNames []string,
 	titles []string,
 	fieldsValues []ast.Expression,
+	condition ast.Expression,
+) (*ast.Group, error) {
+	return selectTableObjects(env, repo, table, fieldsNames, titles, fieldsValues, newTableFilter(env, condition))
+}
+
+// selectTableObjects skips the rows which don't match the filter for the expensive tables
+func selectTableObjects(
+	env *ast.Environment,
+	repo *git.Repository,
+	table string,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
 ) (*ast.Group, error) {
 	switch table {
 	case "refs":
@@ -37,10 +65,40 @@ func SelectGQLObjects(
 		return selectCommits(env, repo, fieldsNames, titles, fieldsValues)
 	case "branches":
 		return selectBranches(env, repo, fieldsNames, titles, fieldsValues)
+	case "remotes":
+		return selectRemotes(env, repo, fieldsNames, titles, fieldsValues)
 	case "diffs":
 		return selectDiffs(env, repo, fieldsNames, titles, fieldsValues)
 	case "tags":
 		return selectTags(env, repo, fieldsNames, titles, fieldsValues)
+	case "files":
+		return selectFiles(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "blame":
+		return selectBlame(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "diff_files":
+		return selectDiffFiles(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "diff_hunks":
+		return selectDiffHunks(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "reflog":
+		return selectReflog(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "stashes":
+		return selectStashes(env, repo, fieldsNames, titles, fieldsValues)
+	case "status":
+		return selectStatus(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "submodules":
+		return selectSubmodules(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "notes":
+		return selectNotes(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "gerrit_changes":
+		return selectGerritChanges(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "gerrit_patchsets":
+		return selectGerritPatchsets(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "gerrit_votes":
+		return selectGerritVotes(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "gerrit_meta":
+		return selectGerritMeta(env, repo, fieldsNames, titles, fieldsValues, filter)
+	case "commit_trailers":
+		return selectCommitTrailers(env, repo, fieldsNames, titles, fieldsValues, filter)
 	default:
 		return selectValues(env, titles, fieldsValues)
 	}
@@ -175,6 +233,28 @@ func selectCommits(
 			case "datetime":
 				timeStamp := commit.Author.When.Unix()
 				values = append(values, ast.DateTimeValue{Value: timeStamp})
+			case "committer_name":
+				values = append(values, ast.TextValue{Value: commit.Committer.Name})
+			case "committer_email":
+				values = append(values, ast.TextValue{Value: commit.Committer.Email})
+			case "commit_datetime":
+				values = append(values, ast.DateTimeValue{Value: commit.Committer.When.Unix()})
+			case "parent_ids":
+				parentIds := make([]string, 0, len(commit.ParentHashes))
+				for _, parentHash := range commit.ParentHashes {
+					parentIds = append(parentIds, parentHash.String())
+				}
+				values = append(values, ast.TextValue{Value: strings.Join(parentIds, " ")})
+			case "parent_count":
+				values = append(values, ast.IntegerValue{Value: int64(commit.NumParents())})
+			case "is_merge":
+				values = append(values, ast.BooleanValue{Value: commit.NumParents() > 1})
+			case "tree_id":
+				values = append(values, ast.TextValue{Value: commit.TreeHash.String()})
+			case "signature":
+				values = append(values, ast.TextValue{Value: commit.PGPSignature})
+			case "is_signed":
+				values = append(values, ast.BooleanValue{Value: commit.PGPSignature != ""})
 			case "repo":
 				value := ast.TextValue{Value: repoPath}
 				values = append(values, value)
@@ -221,6 +301,7 @@ func selectBranches(
 
 	localAndRemoteBranches, _ := repo.References()
 	headRef, _ := repo.Head()
+	repoConfig, _ := repo.Config()
 
 	namesLen := int64(len(fieldsNames))
 	valuesLen := int64(len(fieldsValues))
@@ -233,6 +314,8 @@ func selectBranches(
 		if !ref.Name().IsBranch() && !ref.Name().IsRemote() {
 			return nil
 		}
+		var ahead, behind int64
+		var isCounted, hasUpstream bool
 		var values []ast.Value
 		for index := int64(0); index < namesLen; index++ {
 			fieldName := fieldsNames[index]
@@ -257,6 +340,25 @@ func selectBranches(
 			case "is_remote":
 				isRemote := ref.Name().IsRemote()
 				values = append(values, ast.BooleanValue{Value: isRemote})
+			case "upstream":
+				upstream, ok := branchUpstream(repoConfig, ref.Name())
+				if !ok {
+					values = append(values, ast.NullValue{})
+					continue
+				}
+				values = append(values, ast.TextValue{Value: upstream.String()})
+			case "ahead", "behind":
+				if !isCounted {
+					ahead, behind, hasUpstream = branchAheadBehind(repo, repoConfig, ref.Name(), ref.Hash())
+					isCounted = true
+				}
+				if !hasUpstream {
+					values = append(values, ast.NullValue{})
+				} else if fieldName == "ahead" {
+					values = append(values, ast.IntegerValue{Value: ahead})
+				} else {
+					values = append(values, ast.IntegerValue{Value: behind})
+				}
 			case "repo":
 				values = append(values, ast.TextValue{Value: repoPath})
 			default:
@@ -272,6 +374,210 @@ func selectBranches(
 	return &ast.Group{Rows: rows}, nil
 }
 
+// branchUpstream returns the configured upstream of the local branch
+func branchUpstream(repoConfig *config.Config, name plumbing.ReferenceName) (plumbing.ReferenceName, bool) {
+	if repoConfig == nil || !name.IsBranch() {
+		return "", false
+	}
+
+	branch, ok := repoConfig.Branches[name.Short()]
+	if !ok || branch.Remote == "" || branch.Merge == "" {
+		return "", false
+	}
+
+	if branch.Remote == "." {
+		return branch.Merge, true
+	}
+
+	return plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short()), true
+}
+
+// branchAheadBehind returns the ahead and behind counts of the branch and its upstream
+func branchAheadBehind(
+	repo *git.Repository,
+	repoConfig *config.Config,
+	name plumbing.ReferenceName,
+	hash plumbing.Hash,
+) (int64, int64, bool) {
+	upstream, ok := branchUpstream(repoConfig, name)
+	if !ok {
+		return 0, 0, false
+	}
+
+	upstreamRef, err := repo.Reference(upstream, true)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	ahead, behind, err := aheadBehind(repo, hash, upstreamRef.Hash())
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return ahead, behind, true
+}
+
+// aheadBehind walks both histories until the merge bases like git
+func aheadBehind(repo *git.Repository, local, upstream plumbing.Hash) (int64, int64, error) {
+	flags := make(map[plumbing.Hash]int)
+	walked := make(map[plumbing.Hash]*object.Commit)
+	var queue []*object.Commit
+
+	var mark func(hash plumbing.Hash, flag int) error
+	mark = func(hash plumbing.Hash, flag int) error {
+		if flags[hash]&flag == flag {
+			return nil
+		}
+		if _, ok := flags[hash]; !ok {
+			commit, err := repo.CommitObject(hash)
+			if err != nil {
+				return err
+			}
+			queue = append(queue, commit)
+		}
+		flags[hash] |= flag
+
+		// A walked commit passes its new flag to its ancestors right away
+		if commit, ok := walked[hash]; ok {
+			for _, parent := range commit.ParentHashes {
+				if err := mark(parent, flags[hash]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := mark(local, reachableFromLocal); err != nil {
+		return 0, 0, err
+	}
+	if err := mark(upstream, reachableFromUpstream); err != nil {
+		return 0, 0, err
+	}
+
+	for !isAheadBehindWalked(queue, flags) {
+		newest := 0
+		for index, commit := range queue {
+			if commit.Committer.When.After(queue[newest].Committer.When) {
+				newest = index
+			}
+		}
+		commit := queue[newest]
+		queue = append(queue[:newest], queue[newest+1:]...)
+
+		walked[commit.Hash] = commit
+		for _, parent := range commit.ParentHashes {
+			if err := mark(parent, flags[commit.Hash]); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+
+	var ahead, behind int64
+	for _, flag := range flags {
+		switch flag {
+		case reachableFromLocal:
+			ahead++
+		case reachableFromUpstream:
+			behind++
+		}
+	}
+
+	return ahead, behind, nil
+}
+
+func isAheadBehindWalked(queue []*object.Commit, flags map[plumbing.Hash]int) bool {
+	for _, commit := range queue {
+		if flags[commit.Hash] != reachableFromLocal|reachableFromUpstream {
+			return false
+		}
+	}
+	return true
+}
+
+// nolint:goconst
+func selectRemotes(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	repoConfig, err := repo.Config()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	remotesNames := make([]string, 0, len(repoConfig.Remotes))
+	for name := range repoConfig.Remotes {
+		remotesNames = append(remotesNames, name)
+	}
+	sort.Strings(remotesNames)
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, name := range remotesNames {
+		remote := repoConfig.Remotes[name]
+
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "name":
+				values = append(values, ast.TextValue{Value: remote.Name})
+			case "url":
+				var url string
+				if len(remote.URLs) > 0 {
+					url = remote.URLs[0]
+				}
+				values = append(values, ast.TextValue{Value: url})
+			case "fetch_refspecs":
+				refSpecs := make([]string, 0, len(remote.Fetch))
+				for _, refSpec := range remote.Fetch {
+					refSpecs = append(refSpecs, refSpec.String())
+				}
+				values = append(values, ast.TextValue{Value: strings.Join(refSpecs, " ")})
+			case "push_url":
+				values = append(values, ast.TextValue{Value: remotePushURL(repoConfig, remote)})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+func remotePushURL(repoConfig *config.Config, remote *config.RemoteConfig) string {
+	pushURL := repoConfig.Raw.Section("remote").Subsection(remote.Name).Option("pushurl")
+	if pushURL != "" {
+		return pushURL
+	}
+
+	if len(remote.URLs) > 0 {
+		return remote.URLs[0]
+	}
+	return ""
+}
+
 // nolint:goconst,gocyclo
 func selectDiffs(
 	env *ast.Environment,
@@ -379,6 +685,11 @@ func selectTags(
 	padding := namesLen - valuesLen
 
 	_ = tags.ForEach(func(ref *plumbing.Reference) error {
+		tag, commitId, err := peelTag(repo, ref)
+		if err != nil {
+			return nil
+		}
+
 		var values []ast.Value
 		for index := int64(0); index < namesLen; index++ {
 			fieldName := fieldsNames[index]
@@ -390,12 +701,28 @@ func selectTags(
 					continue
 				}
 			}
+			if tag == nil && isAnnotatedTagField(fieldName) {
+				values = append(values, ast.NullValue{})
+				continue
+			}
 			switch fieldName {
 			case "name":
-				if ref.Name().IsTag() {
-					tagName := ref.Name().Short()
-					values = append(values, ast.TextValue{Value: tagName})
-				}
+				tagName := ref.Name().Short()
+				values = append(values, ast.TextValue{Value: tagName})
+			case "commit_id":
+				values = append(values, ast.TextValue{Value: commitId.String()})
+			case "tag_object_id":
+				values = append(values, ast.TextValue{Value: tag.Hash.String()})
+			case "is_annotated":
+				values = append(values, ast.BooleanValue{Value: tag != nil})
+			case "tagger_name":
+				values = append(values, ast.TextValue{Value: tag.Tagger.Name})
+			case "tagger_email":
+				values = append(values, ast.TextValue{Value: tag.Tagger.Email})
+			case "datetime":
+				values = append(values, ast.DateTimeValue{Value: tag.Tagger.When.Unix()})
+			case "message":
+				values = append(values, ast.TextValue{Value: tag.Message})
 			case "repo":
 				value := ast.TextValue{Value: repoPath}
 				values = append(values, value)
@@ -412,82 +739,1320 @@ func selectTags(
 	return &ast.Group{Rows: rows}, nil
 }
 
-func selectValues(
+// peelTag returns the annotated tag object, nil for lightweight tags, and the tagged commit
+func peelTag(repo *git.Repository, ref *plumbing.Reference) (*object.Tag, plumbing.Hash, error) {
+	tag, err := repo.TagObject(ref.Hash())
+	if err == plumbing.ErrObjectNotFound {
+		return nil, ref.Hash(), nil
+	}
+	if err != nil {
+		return nil, plumbing.ZeroHash, err
+	}
+
+	target := tag
+	for target.TargetType == plumbing.TagObject {
+		if target, err = repo.TagObject(target.Target); err != nil {
+			return nil, plumbing.ZeroHash, err
+		}
+	}
+
+	return tag, target.Target, nil
+}
+
+func isAnnotatedTagField(fieldName string) bool {
+	switch fieldName {
+	case "tag_object_id", "tagger_name", "tagger_email", "datetime", "message":
+		return true
+	default:
+		return false
+	}
+}
+
+// nolint:goconst
+func selectFiles(
 	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
 	titles []string,
 	fieldsValues []ast.Expression,
+	filter tableFilter,
 ) (*ast.Group, error) {
-	var group ast.Group
-	var values []ast.Value
+	var rows []ast.Row
 
-	for _, value := range fieldsValues {
-		evaluated, _ := EvaluateExpression(env, value, titles, values)
-		values = append(values, evaluated)
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	revision, commit, err := revisionCommit(repo, filter)
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return &ast.Group{Rows: rows}, nil
 	}
 
-	row := ast.Row{Values: values}
-	group.Rows = append(group.Rows, row)
+	files, err := commit.Files()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
 
-	return &group, nil
-}
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
 
-func GetColumnName(aliasTable map[string]string, name string) string {
-	if columnName, ok := aliasTable[name]; ok {
-		return columnName
-	}
+	_ = files.ForEach(func(file *object.File) error {
+		if !filter.Matches("path", ast.TextValue{Value: file.Name}) {
+			return nil
+		}
 
-	return name
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "path":
+				values = append(values, ast.TextValue{Value: file.Name})
+			case "name":
+				values = append(values, ast.TextValue{Value: path.Base(file.Name)})
+			case "extension":
+				extension := strings.TrimPrefix(path.Ext(file.Name), ".")
+				values = append(values, ast.TextValue{Value: extension})
+			case "size":
+				values = append(values, ast.IntegerValue{Value: file.Size})
+			case "mode":
+				mode := fmt.Sprintf("%06o", uint32(file.Mode))
+				values = append(values, ast.TextValue{Value: mode})
+			case "blob_id":
+				values = append(values, ast.TextValue{Value: file.Hash.String()})
+			case "is_binary":
+				isBinary, _ := file.IsBinary()
+				values = append(values, ast.BooleanValue{Value: isBinary})
+			case "revision":
+				values = append(values, ast.TextValue{Value: revision})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+		return nil
+	})
+
+	return &ast.Group{Rows: rows}, nil
 }
 
-// GetChangeIdFromCommitMessageFooter
-// https://gerrit-review.googlesource.com/Documentation/user-changeid.html
-// https://gerrit.googlesource.com/gerrit/+/refs/heads/master/java/com/google/gerrit/server/util/CommitMessageUtil.java
-// https://github.com/eclipse-jgit/jgit/blob/master/org.eclipse.jgit/src/org/eclipse/jgit/util/ChangeIdUtil.java
-// https://github.com/eclipse-jgit/jgit/blob/master/org.eclipse.jgit.test/tst/org/eclipse/jgit/util/ChangeIdUtilTest.java
-func GetChangeIdFromCommitMessageFooter(message string) string {
-	isEmptyLine := func(line string) bool {
-		return strings.TrimSpace(line) == ""
+// nolint:goconst
+func selectBlame(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	revision, commit, err := revisionCommit(repo, filter)
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return &ast.Group{Rows: rows}, nil
 	}
 
-	trimRight := func(s string) string {
-		return trailingWhitespace.ReplaceAllString(s, "")
+	files, err := commit.Files()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
 	}
 
-	indexOfChangeId := func(message, delimiter string) int {
-		lines := strings.Split(message, delimiter)
-		if len(lines) == 0 {
-			return -1
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	_ = files.ForEach(func(file *object.File) error {
+		if !filter.Matches("path", ast.TextValue{Value: file.Name}) {
+			return nil
 		}
-		indexOfChangeIdLine := 0
-		inFooter := false
-		for i := len(lines) - 1; i >= 0; i-- {
-			if !inFooter && isEmptyLine(lines[i]) {
-				continue
-			}
-			inFooter = true
-			if changeIdPattern.MatchString(trimRight(lines[i])) {
-				indexOfChangeIdLine = i
-				break
-			} else if isEmptyLine(lines[i]) || i == 0 {
-				return -1
-			}
+
+		if isBinary, err := file.IsBinary(); err != nil || isBinary {
+			return nil
 		}
-		indexOfChangeIdLineInString := 0
-		for i := 0; i < indexOfChangeIdLine; i++ {
-			indexOfChangeIdLineInString += len(lines[i]) + len(delimiter)
+
+		result, err := git.Blame(commit, file.Name)
+		if err != nil {
+			return nil
 		}
-		return indexOfChangeIdLineInString + strings.Index(lines[indexOfChangeIdLine], changeIdPrefix)
-	}
 
-	index := indexOfChangeId(message, messageDelimiter)
-	if index == -1 {
-		return ""
-	}
+		for lineIndex, line := range result.Lines {
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "path":
+					values = append(values, ast.TextValue{Value: file.Name})
+				case "line_no":
+					values = append(values, ast.IntegerValue{Value: int64(lineIndex + 1)})
+				case "line":
+					values = append(values, ast.TextValue{Value: line.Text})
+				case "commit_id":
+					values = append(values, ast.TextValue{Value: line.Hash.String()})
+				case "name":
+					values = append(values, ast.TextValue{Value: line.AuthorName})
+				case "email":
+					values = append(values, ast.TextValue{Value: line.Author})
+				case "datetime":
+					values = append(values, ast.DateTimeValue{Value: line.Date.Unix()})
+				case "revision":
+					values = append(values, ast.TextValue{Value: revision})
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+		}
+		return nil
+	})
 
-	matches := changeIdPattern.FindAllString(message[index:], -1)
-	if len(matches) == 0 {
-		return ""
-	}
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectDiffFiles(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
 
-	return strings.TrimSpace(strings.TrimPrefix(matches[0], changeIdPrefix))
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	commitObjects, err := repo.CommitObjects()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	_ = commitObjects.ForEach(func(commit *object.Commit) error {
+		commitId := commit.ID().String()
+		if !filter.Matches("commit_id", ast.TextValue{Value: commitId}) {
+			return nil
+		}
+
+		changes, err := commitChanges(commit)
+		if err != nil {
+			return nil
+		}
+
+		for _, change := range changes {
+			changePath, oldPath, changeType := changePaths(change)
+			if !filter.Matches("path", ast.TextValue{Value: changePath}) {
+				continue
+			}
+
+			var patch *object.Patch
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "commit_id":
+					values = append(values, ast.TextValue{Value: commitId})
+				case "path":
+					values = append(values, ast.TextValue{Value: changePath})
+				case "old_path":
+					values = append(values, ast.TextValue{Value: oldPath})
+				case "change_type":
+					values = append(values, ast.TextValue{Value: changeType})
+				case "insertions", "deletions", "is_binary":
+					if patch == nil {
+						patch, _ = change.Patch()
+					}
+					var insertions, deletions int64
+					var isBinary bool
+					if patch != nil {
+						for _, stat := range patch.Stats() {
+							insertions += int64(stat.Addition)
+							deletions += int64(stat.Deletion)
+						}
+						for _, filePatch := range patch.FilePatches() {
+							isBinary = isBinary || filePatch.IsBinary()
+						}
+					}
+					if fieldName == "insertions" {
+						values = append(values, ast.IntegerValue{Value: insertions})
+					} else if fieldName == "deletions" {
+						values = append(values, ast.IntegerValue{Value: deletions})
+					} else {
+						values = append(values, ast.BooleanValue{Value: isBinary})
+					}
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+		}
+		return nil
+	})
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectDiffHunks(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	commitObjects, err := repo.CommitObjects()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	_ = commitObjects.ForEach(func(commit *object.Commit) error {
+		commitId := commit.ID().String()
+		if !filter.Matches("commit_id", ast.TextValue{Value: commitId}) {
+			return nil
+		}
+
+		changes, err := commitChanges(commit)
+		if err != nil {
+			return nil
+		}
+
+		for _, change := range changes {
+			changePath, _, _ := changePaths(change)
+			if !filter.Matches("path", ast.TextValue{Value: changePath}) {
+				continue
+			}
+
+			patch, err := change.Patch()
+			if err != nil {
+				continue
+			}
+
+			for _, filePatch := range patch.FilePatches() {
+				for hunkIndex, hunk := range diffHunks(filePatch.Chunks(), hunkContextLines) {
+					hunkLines := []diffLine{{text: hunk.Header()}}
+					for _, line := range hunk.lines {
+						if line.operation != diff.Equal {
+							hunkLines = append(hunkLines, line)
+						}
+					}
+
+					for lineIndex, line := range hunkLines {
+						var values []ast.Value
+						for index := int64(0); index < namesLen; index++ {
+							fieldName := fieldsNames[index]
+							if index-padding >= 0 {
+								value := fieldsValues[index-padding]
+								if _, ok := value.(*ast.SymbolExpression); !ok {
+									evaluated, _ := EvaluateExpression(env, value, titles, values)
+									values = append(values, evaluated)
+									continue
+								}
+							}
+							switch fieldName {
+							case "commit_id":
+								values = append(values, ast.TextValue{Value: commitId})
+							case "path":
+								values = append(values, ast.TextValue{Value: changePath})
+							case "hunk_index":
+								values = append(values, ast.IntegerValue{Value: int64(hunkIndex)})
+							case "old_start":
+								values = append(values, ast.IntegerValue{Value: int64(hunk.oldStart)})
+							case "old_lines":
+								values = append(values, ast.IntegerValue{Value: int64(hunk.oldLines)})
+							case "new_start":
+								values = append(values, ast.IntegerValue{Value: int64(hunk.newStart)})
+							case "new_lines":
+								values = append(values, ast.IntegerValue{Value: int64(hunk.newLines)})
+							case "kind":
+								values = append(values, ast.TextValue{Value: hunkLineKind(lineIndex, line)})
+							case "content":
+								values = append(values, ast.TextValue{Value: line.text})
+							case "repo":
+								values = append(values, ast.TextValue{Value: repoPath})
+							default:
+								values = append(values, ast.NullValue{})
+							}
+						}
+						rows = append(rows, ast.Row{Values: values})
+					}
+				}
+			}
+		}
+		return nil
+	})
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+func hunkLineKind(lineIndex int, line diffLine) string {
+	switch {
+	case lineIndex == 0:
+		return "header"
+	case line.operation == diff.Add:
+		return "added"
+	default:
+		return "removed"
+	}
+}
+
+// commitChanges returns the changes from the first parent with the renames detection
+func commitChanges(commit *object.Commit) (object.Changes, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+
+	return object.DiffTreeWithOptions(context.Background(), parentTree, tree, object.DefaultDiffTreeOptions)
+}
+
+func changePaths(change *object.Change) (string, string, string) {
+	switch {
+	case change.From.Name == "":
+		return change.To.Name, "", "added"
+	case change.To.Name == "":
+		return change.From.Name, change.From.Name, "deleted"
+	case change.From.Name != change.To.Name:
+		return change.To.Name, change.From.Name, "renamed"
+	default:
+		return change.To.Name, change.From.Name, "modified"
+	}
+}
+
+// The reflogs are read from the logs directory because the storage doesn't parse them
+// nolint:goconst
+func selectReflog(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return &ast.Group{Rows: rows}, nil
+	}
+	repoPath := storer.Filesystem().Root()
+
+	refs, err := reflogRefs(storer.Filesystem())
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, ref := range refs {
+		if !filter.Matches("ref", ast.TextValue{Value: ref}) {
+			continue
+		}
+
+		entries, err := readReflog(storer.Filesystem(), ref)
+		if err != nil {
+			continue
+		}
+
+		for entryIndex, entry := range entries {
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "ref":
+					values = append(values, ast.TextValue{Value: ref})
+				case "index":
+					values = append(values, ast.IntegerValue{Value: int64(entryIndex)})
+				case "old_id":
+					values = append(values, ast.TextValue{Value: entry.oldId})
+				case "new_id":
+					values = append(values, ast.TextValue{Value: entry.newId})
+				case "name":
+					values = append(values, ast.TextValue{Value: entry.signature.Name})
+				case "email":
+					values = append(values, ast.TextValue{Value: entry.signature.Email})
+				case "datetime":
+					values = append(values, ast.DateTimeValue{Value: entry.signature.When.Unix()})
+				case "message":
+					values = append(values, ast.TextValue{Value: entry.message})
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+		}
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectStashes(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return &ast.Group{Rows: rows}, nil
+	}
+	repoPath := storer.Filesystem().Root()
+
+	entries, err := readReflog(storer.Filesystem(), stashRef)
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for entryIndex, entry := range entries {
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "index":
+				values = append(values, ast.IntegerValue{Value: int64(entryIndex)})
+			case "commit_id":
+				values = append(values, ast.TextValue{Value: entry.newId})
+			case "message":
+				values = append(values, ast.TextValue{Value: entry.message})
+			case "branch":
+				values = append(values, ast.TextValue{Value: stashBranch(entry.message)})
+			case "datetime":
+				values = append(values, ast.DateTimeValue{Value: entry.signature.When.Unix()})
+			case "files_changed":
+				commit, err := repo.CommitObject(plumbing.NewHash(entry.newId))
+				if err != nil {
+					values = append(values, ast.NullValue{})
+					continue
+				}
+				changes, err := commitChanges(commit)
+				if err != nil {
+					values = append(values, ast.NullValue{})
+					continue
+				}
+				values = append(values, ast.IntegerValue{Value: int64(len(changes))})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectStatus(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	paths := make([]string, 0, len(status))
+	for filePath, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified && fileStatus.Worktree == git.Unmodified {
+			continue
+		}
+		if !filter.Matches("path", ast.TextValue{Value: filePath}) {
+			continue
+		}
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, filePath := range paths {
+		fileStatus := status[filePath]
+
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "path":
+				values = append(values, ast.TextValue{Value: filePath})
+			case "staging":
+				values = append(values, ast.TextValue{Value: statusCodeName(fileStatus.Staging)})
+			case "worktree":
+				values = append(values, ast.TextValue{Value: statusCodeName(fileStatus.Worktree)})
+			case "is_untracked":
+				values = append(values, ast.BooleanValue{Value: fileStatus.Worktree == git.Untracked})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+func statusCodeName(code git.StatusCode) string {
+	switch code {
+	case git.Unmodified:
+		return "unmodified"
+	case git.Untracked:
+		return "untracked"
+	case git.Modified:
+		return "modified"
+	case git.Added:
+		return "added"
+	case git.Deleted:
+		return "deleted"
+	case git.Renamed:
+		return "renamed"
+	case git.Copied:
+		return "copied"
+	case git.UpdatedButUnmerged:
+		return "unmerged"
+	default:
+		return string(code)
+	}
+}
+
+// nolint:goconst
+func selectSubmodules(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	revision, commit, err := revisionCommit(repo, filter)
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	modules, err := revisionModules(commit)
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	repoConfig, _ := repo.Config()
+
+	modulesNames := make([]string, 0, len(modules.Submodules))
+	for name := range modules.Submodules {
+		modulesNames = append(modulesNames, name)
+	}
+	sort.Strings(modulesNames)
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, name := range modulesNames {
+		submodule := modules.Submodules[name]
+		if !filter.Matches("path", ast.TextValue{Value: submodule.Path}) {
+			continue
+		}
+
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "name":
+				values = append(values, ast.TextValue{Value: submodule.Name})
+			case "path":
+				values = append(values, ast.TextValue{Value: submodule.Path})
+			case "url":
+				values = append(values, ast.TextValue{Value: submodule.URL})
+			case "branch":
+				values = append(values, ast.TextValue{Value: submodule.Branch})
+			case "commit_id":
+				entry, err := tree.FindEntry(submodule.Path)
+				if err != nil || entry.Mode != filemode.Submodule {
+					values = append(values, ast.NullValue{})
+					continue
+				}
+				values = append(values, ast.TextValue{Value: entry.Hash.String()})
+			case "is_initialized":
+				var isInitialized bool
+				if repoConfig != nil {
+					_, isInitialized = repoConfig.Submodules[submodule.Name]
+				}
+				values = append(values, ast.BooleanValue{Value: isInitialized})
+			case "revision":
+				values = append(values, ast.TextValue{Value: revision})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+func revisionModules(commit *object.Commit) (*config.Modules, error) {
+	modules := config.NewModules()
+
+	file, err := commit.File(gitModulesFile)
+	if err == object.ErrFileNotFound {
+		return modules, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+
+	err = modules.Unmarshal([]byte(content))
+	return modules, err
+}
+
+// nolint:goconst
+func selectNotes(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	gitReferences, err := repo.References()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	_ = gitReferences.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || !ref.Name().IsNote() {
+			return nil
+		}
+
+		notesRef := ref.Name().String()
+		if !filter.Matches("notes_ref", ast.TextValue{Value: notesRef}) {
+			return nil
+		}
+
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil
+		}
+
+		files, err := commit.Files()
+		if err != nil {
+			return nil
+		}
+
+		_ = files.ForEach(func(file *object.File) error {
+			commitId := strings.ReplaceAll(file.Name, "/", "")
+			if !plumbing.IsHash(commitId) || !filter.Matches("commit_id", ast.TextValue{Value: commitId}) {
+				return nil
+			}
+
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "notes_ref":
+					values = append(values, ast.TextValue{Value: notesRef})
+				case "commit_id":
+					values = append(values, ast.TextValue{Value: commitId})
+				case "note":
+					note, _ := file.Contents()
+					values = append(values, ast.TextValue{Value: note})
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+			return nil
+		})
+		return nil
+	})
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectGerritChanges(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	changes, err := gerritChanges(repo)
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, change := range changes {
+		if len(change.patchsets) == 0 || !filter.Matches("change_number", ast.IntegerValue{Value: change.changeNumber}) {
+			continue
+		}
+
+		first, err := repo.CommitObject(change.patchsets[0].commitId)
+		if err != nil {
+			continue
+		}
+
+		current := change.patchsets[len(change.patchsets)-1]
+		currentCommit, err := repo.CommitObject(current.commitId)
+		if err != nil {
+			continue
+		}
+
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "change_number":
+				values = append(values, ast.IntegerValue{Value: change.changeNumber})
+			case "change_id":
+				changeId := GetChangeIdFromCommitMessageFooter(currentCommit.Message)
+				values = append(values, ast.TextValue{Value: changeId})
+			case "patchset_count":
+				values = append(values, ast.IntegerValue{Value: int64(len(change.patchsets))})
+			case "current_patchset":
+				values = append(values, ast.IntegerValue{Value: current.patchsetNumber})
+			case "commit_id":
+				values = append(values, ast.TextValue{Value: current.commitId.String()})
+			case "uploader_name":
+				values = append(values, ast.TextValue{Value: first.Committer.Name})
+			case "uploader_email":
+				values = append(values, ast.TextValue{Value: first.Committer.Email})
+			case "datetime":
+				values = append(values, ast.DateTimeValue{Value: first.Committer.When.Unix()})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectGerritPatchsets(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	changes, err := gerritChanges(repo)
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, change := range changes {
+		if !filter.Matches("change_number", ast.IntegerValue{Value: change.changeNumber}) {
+			continue
+		}
+
+		for _, patchset := range change.patchsets {
+			commit, err := repo.CommitObject(patchset.commitId)
+			if err != nil {
+				continue
+			}
+
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "change_number":
+					values = append(values, ast.IntegerValue{Value: patchset.changeNumber})
+				case "patchset_number":
+					values = append(values, ast.IntegerValue{Value: patchset.patchsetNumber})
+				case "commit_id":
+					values = append(values, ast.TextValue{Value: patchset.commitId.String()})
+				case "change_id":
+					changeId := GetChangeIdFromCommitMessageFooter(commit.Message)
+					values = append(values, ast.TextValue{Value: changeId})
+				case "uploader_name":
+					values = append(values, ast.TextValue{Value: commit.Committer.Name})
+				case "uploader_email":
+					values = append(values, ast.TextValue{Value: commit.Committer.Email})
+				case "datetime":
+					values = append(values, ast.DateTimeValue{Value: commit.Committer.When.Unix()})
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+		}
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectGerritVotes(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	changes, err := gerritChanges(repo)
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, change := range changes {
+		if change.metaId.IsZero() || !filter.Matches("change_number", ast.IntegerValue{Value: change.changeNumber}) {
+			continue
+		}
+
+		meta, err := readGerritMeta(repo, change.metaId)
+		if err != nil {
+			continue
+		}
+
+		for _, vote := range meta.votes {
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "change_number":
+					values = append(values, ast.IntegerValue{Value: change.changeNumber})
+				case "patchset_number":
+					values = append(values, ast.IntegerValue{Value: vote.patchsetNumber})
+				case "label":
+					values = append(values, ast.TextValue{Value: vote.label})
+				case "value":
+					values = append(values, ast.IntegerValue{Value: vote.value})
+				case "voter":
+					values = append(values, ast.TextValue{Value: vote.voter})
+				case "datetime":
+					values = append(values, ast.DateTimeValue{Value: vote.datetime})
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+		}
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectGerritMeta(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	changes, err := gerritChanges(repo)
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, change := range changes {
+		if change.metaId.IsZero() || !filter.Matches("change_number", ast.IntegerValue{Value: change.changeNumber}) {
+			continue
+		}
+
+		meta, err := readGerritMeta(repo, change.metaId)
+		if err != nil {
+			continue
+		}
+
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, _ := EvaluateExpression(env, value, titles, values)
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			switch fieldName {
+			case "change_number":
+				values = append(values, ast.IntegerValue{Value: change.changeNumber})
+			case "status":
+				values = append(values, ast.TextValue{Value: meta.status})
+			case "topic":
+				values = append(values, ast.TextValue{Value: meta.topic})
+			case "hashtags":
+				values = append(values, ast.TextValue{Value: meta.hashtags})
+			case "submitter":
+				values = append(values, ast.TextValue{Value: meta.submitter})
+			case "repo":
+				values = append(values, ast.TextValue{Value: repoPath})
+			default:
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// nolint:goconst
+func selectCommitTrailers(
+	env *ast.Environment,
+	repo *git.Repository,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+	filter tableFilter,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	repoPath := storer.Filesystem().Root()
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	commitObjects, err := repo.CommitObjects()
+	if err != nil {
+		return &ast.Group{Rows: rows}, nil
+	}
+
+	_ = commitObjects.ForEach(func(commit *object.Commit) error {
+		commitId := commit.ID().String()
+		if !filter.Matches("commit_id", ast.TextValue{Value: commitId}) {
+			return nil
+		}
+
+		for position, trailer := range ast.ParseTrailers(commit.Message) {
+			if !filter.Matches("key", ast.TextValue{Value: trailer.Key}) {
+				continue
+			}
+
+			var values []ast.Value
+			for index := int64(0); index < namesLen; index++ {
+				fieldName := fieldsNames[index]
+				if index-padding >= 0 {
+					value := fieldsValues[index-padding]
+					if _, ok := value.(*ast.SymbolExpression); !ok {
+						evaluated, _ := EvaluateExpression(env, value, titles, values)
+						values = append(values, evaluated)
+						continue
+					}
+				}
+				switch fieldName {
+				case "commit_id":
+					values = append(values, ast.TextValue{Value: commitId})
+				case "key":
+					values = append(values, ast.TextValue{Value: trailer.Key})
+				case "value":
+					values = append(values, ast.TextValue{Value: trailer.Value})
+				case "position":
+					values = append(values, ast.IntegerValue{Value: int64(position + 1)})
+				case "repo":
+					values = append(values, ast.TextValue{Value: repoPath})
+				default:
+					values = append(values, ast.NullValue{})
+				}
+			}
+			rows = append(rows, ast.Row{Values: values})
+		}
+		return nil
+	})
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// revisionCommit returns the commit of the `revision = value` condition or of HEAD
+func revisionCommit(repo *git.Repository, filter tableFilter) (string, *object.Commit, error) {
+	value, ok := filter.Value("revision")
+	if !ok {
+		head, err := repo.Head()
+		if err != nil {
+			return "HEAD", nil, nil
+		}
+
+		commit, err := repo.CommitObject(head.Hash())
+		return "HEAD", commit, err
+	}
+
+	revision := value.AsText()
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return revision, nil, fmt.Errorf("%w %s", errUnknownRevision, revision)
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return revision, nil, fmt.Errorf("revision %s is not a commit", revision)
+	}
+	return revision, commit, nil
+}
+
+func selectValues(
+	env *ast.Environment,
+	titles []string,
+	fieldsValues []ast.Expression,
+) (*ast.Group, error) {
+	var group ast.Group
+	var values []ast.Value
+
+	for _, value := range fieldsValues {
+		evaluated, _ := EvaluateExpression(env, value, titles, values)
+		values = append(values, evaluated)
+	}
+
+	row := ast.Row{Values: values}
+	group.Rows = append(group.Rows, row)
+
+	return &group, nil
+}
+
+func GetColumnName(aliasTable map[string]string, name string) string {
+	if columnName, ok := aliasTable[name]; ok {
+		return columnName
+	}
+
+	return name
+}
+
+// GetChangeIdFromCommitMessageFooter returns the last `Change-Id` of the commit message trailers
+// https://gerrit-review.googlesource.com/Documentation/user-changeid.html
+// https://github.com/eclipse-jgit/jgit/blob/master/org.eclipse.jgit/src/org/eclipse/jgit/util/ChangeIdUtil.java
+// https://github.com/eclipse-jgit/jgit/blob/master/org.eclipse.jgit.test/tst/org/eclipse/jgit/util/ChangeIdUtilTest.java
+func GetChangeIdFromCommitMessageFooter(message string) string {
+	trailers := ast.ParseTrailers(message)
+	for index := len(trailers) - 1; index >= 0; index-- {
+		trailer := trailers[index]
+		if trailer.Key == changeIdKey && changeIdValuePattern.MatchString(trailer.Value) {
+			return trailer.Value
+		}
+	}
+	return ""
 }
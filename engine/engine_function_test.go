@@ -6,9 +6,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-git/go-billy/v5/util"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ggql/ggql/ast"
 )
@@ -48,12 +56,33 @@ func newFunctionRepo() *git.Repository {
 	// Create a new tag
 	ref, _ := repo.Head()
 	_, _ = repo.CreateTag("v0.0.1", ref.Hash(), &git.CreateTagOptions{
+		Tagger: &object.Signature{
+			Name:  "name",
+			Email: "name@example.com",
+			When:  time.Now(),
+		},
 		Message: "Create tag",
 	})
 
 	return repo
 }
 
+func renameFunctionFile(repo *git.Repository, name string) *object.Commit {
+	tree, _ := repo.Worktree()
+
+	_, _ = tree.Move(functionFile, name)
+	commit, _ := tree.Commit("Renaming "+functionFile, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "name",
+			Email: "name@example.com",
+			When:  time.Now(),
+		},
+	})
+
+	commitObject, _ := repo.CommitObject(commit)
+	return commitObject
+}
+
 func deleteFunctionRepo() {
 	_ = os.RemoveAll(functionRepo)
 }
@@ -79,7 +108,7 @@ func TestSelectGQLObjects(t *testing.T) {
 		},
 	}
 
-	_, err := SelectGQLObjects(&env, repo, table, fieldsNames, titles, fieldsValues)
+	_, err := SelectGQLObjects(&env, repo, table, fieldsNames, titles, fieldsValues, nil)
 	assert.Equal(t, nil, err)
 }
 
@@ -131,6 +160,66 @@ func TestSelectCommits(t *testing.T) {
 	assert.Equal(t, len(fieldsNames), len(group.Rows[0].Values))
 }
 
+func TestSelectCommitsCommitterAndParents(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	root, _ := repo.CommitObject(head.Hash())
+	commit := renameFunctionFile(repo, "renamed.txt")
+
+	author := object.Signature{Name: "author", Email: "author@example.com", When: time.Unix(1700000000, 0)}
+	committer := object.Signature{Name: "committer", Email: "committer@example.com", When: time.Unix(1700000100, 0)}
+	mergeHash := storeObject(repo, &object.Commit{
+		Author:       author,
+		Committer:    committer,
+		Message:      "Merge",
+		TreeHash:     commit.TreeHash,
+		ParentHashes: []plumbing.Hash{root.Hash, commit.Hash},
+		PGPSignature: "-----BEGIN PGP SIGNATURE-----\n\nsignature\n-----END PGP SIGNATURE-----\n",
+	})
+
+	fieldsNames := []string{"commit_id", "committer_name", "committer_email", "commit_datetime", "parent_ids",
+		"parent_count", "is_merge", "tree_id", "is_signed"}
+	titles := fieldsNames
+
+	group, err := selectCommits(&env, repo, fieldsNames, titles, nil)
+	assert.Equal(t, nil, err)
+
+	rows := make(map[string][]ast.Value)
+	for _, row := range group.Rows {
+		rows[row.Values[0].AsText()] = row.Values
+	}
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: mergeHash.String()},
+		ast.TextValue{Value: "committer"},
+		ast.TextValue{Value: "committer@example.com"},
+		ast.DateTimeValue{Value: 1700000100},
+		ast.TextValue{Value: root.Hash.String() + " " + commit.Hash.String()},
+		ast.IntegerValue{Value: 2},
+		ast.BooleanValue{Value: true},
+		ast.TextValue{Value: commit.TreeHash.String()},
+		ast.BooleanValue{Value: true},
+	}, rows[mergeHash.String()])
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: root.Hash.String()},
+		ast.TextValue{Value: "name"},
+		ast.TextValue{Value: "name@example.com"},
+		ast.DateTimeValue{Value: root.Committer.When.Unix()},
+		ast.TextValue{Value: ""},
+		ast.IntegerValue{Value: 0},
+		ast.BooleanValue{Value: false},
+		ast.TextValue{Value: root.TreeHash.String()},
+		ast.BooleanValue{Value: false},
+	}, rows[root.Hash.String()])
+}
+
 func TestSelectBranches(t *testing.T) {
 	env := ast.Environment{
 		Globals:      map[string]ast.Value{},
@@ -153,6 +242,235 @@ func TestSelectBranches(t *testing.T) {
 	assert.Equal(t, nil, err)
 	assert.Equal(t, 1, len(group.Rows))
 	assert.Equal(t, len(fieldsNames), len(group.Rows[0].Values))
+
+	// The branches without upstream have no tracking information
+	fieldsNames = []string{"upstream", "ahead", "behind"}
+
+	group, err = selectBranches(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []ast.Value{ast.NullValue{}, ast.NullValue{}, ast.NullValue{}}, group.Rows[0].Values)
+}
+
+func TestSelectBranchesUpstream(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	upstream := plumbing.NewRemoteReferenceName("origin", head.Name().Short())
+	_ = repo.Storer.SetReference(plumbing.NewHashReference(upstream, head.Hash()))
+	_, _ = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/repo.git"}})
+	_ = repo.CreateBranch(&config.Branch{Name: head.Name().Short(), Remote: "origin", Merge: head.Name()})
+
+	_ = renameFunctionFile(repo, "renamed.txt")
+
+	fieldsNames := []string{"name", "upstream", "ahead", "behind"}
+	branchEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "name"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: head.Name().String(), ValueType: ast.StringValueText},
+	}
+
+	group, err := selectBranches(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(group.Rows))
+
+	for _, row := range group.Rows {
+		isMatch, _ := EvaluateExpression(&env, branchEquals, fieldsNames, row.Values)
+		if !isMatch.AsBool() {
+			assert.Equal(t, ast.NullValue{}, row.Values[1])
+			continue
+		}
+		assert.Equal(t, []ast.Value{
+			ast.TextValue{Value: head.Name().String()},
+			ast.TextValue{Value: upstream.String()},
+			ast.IntegerValue{Value: 1},
+			ast.IntegerValue{Value: 0},
+		}, row.Values)
+	}
+
+	// The NULL columns of the branches without upstream never match a filter
+	for expected, condition := range map[int]ast.Expression{
+		1: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "ahead"},
+			Operator: ast.COEqual,
+			Right:    &ast.NumberExpression{Value: ast.IntegerValue{Value: 1}},
+		},
+		0: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "upstream"},
+			Operator: ast.COEqual,
+			Right:    &ast.StringExpression{Value: "refs/remotes/origin/gone", ValueType: ast.StringValueText},
+		},
+	} {
+		gitqlObject := ast.GitQLObject{Titles: fieldsNames, Groups: []ast.Group{*group}}
+		err = executeWhereStatement(&env, &ast.WhereStatement{Condition: condition}, &gitqlObject)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, expected, len(gitqlObject.Groups[0].Rows))
+	}
+}
+
+func TestBranchUpstream(t *testing.T) {
+	repoConfig := config.NewConfig()
+	repoConfig.Branches["main"] = &config.Branch{Name: "main", Remote: "origin", Merge: "refs/heads/trunk"}
+	repoConfig.Branches["local"] = &config.Branch{Name: "local", Remote: ".", Merge: "refs/heads/main"}
+
+	upstream, ok := branchUpstream(repoConfig, plumbing.NewBranchReferenceName("main"))
+	assert.True(t, ok)
+	assert.Equal(t, plumbing.ReferenceName("refs/remotes/origin/trunk"), upstream)
+
+	upstream, ok = branchUpstream(repoConfig, plumbing.NewBranchReferenceName("local"))
+	assert.True(t, ok)
+	assert.Equal(t, plumbing.ReferenceName("refs/heads/main"), upstream)
+
+	_, ok = branchUpstream(repoConfig, plumbing.NewBranchReferenceName("other"))
+	assert.False(t, ok)
+
+	_, ok = branchUpstream(repoConfig, plumbing.NewRemoteReferenceName("origin", "main"))
+	assert.False(t, ok)
+
+	_, ok = branchUpstream(nil, plumbing.NewBranchReferenceName("main"))
+	assert.False(t, ok)
+}
+
+func TestAheadBehind(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	commit := renameFunctionFile(repo, "renamed.txt")
+
+	ahead, behind, err := aheadBehind(repo, commit.Hash, head.Hash())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), ahead)
+	assert.Equal(t, int64(0), behind)
+
+	ahead, behind, err = aheadBehind(repo, head.Hash(), commit.Hash)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), ahead)
+	assert.Equal(t, int64(1), behind)
+
+	_, _, err = aheadBehind(repo, plumbing.ZeroHash, commit.Hash)
+	assert.NotNil(t, err)
+}
+
+func TestAheadBehindDiverged(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	base, _ := repo.CommitObject(head.Hash())
+
+	// Both commits follow the base which is the merge base
+	newCommit := func(message string, when int64) plumbing.Hash {
+		signature := object.Signature{Name: "name", Email: "name@example.com", When: time.Unix(when, 0)}
+		return storeObject(repo, &object.Commit{
+			Author:       signature,
+			Committer:    signature,
+			Message:      message,
+			TreeHash:     base.TreeHash,
+			ParentHashes: []plumbing.Hash{base.Hash},
+		})
+	}
+	local := newCommit("Local", base.Committer.When.Unix()+1)
+	upstream := newCommit("Upstream", base.Committer.When.Unix()+2)
+
+	ahead, behind, err := aheadBehind(repo, local, upstream)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), ahead)
+	assert.Equal(t, int64(1), behind)
+
+	ahead, behind, err = aheadBehind(repo, local, local)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), ahead)
+	assert.Equal(t, int64(0), behind)
+
+	// The commits with the same time as their ancestors are counted once they are reachable from both
+	signature := object.Signature{Name: "name", Email: "name@example.com", When: base.Committer.When}
+	parent := base.Hash
+	var chain []plumbing.Hash
+	for _, message := range []string{"First", "Second", "Third"} {
+		parent = storeObject(repo, &object.Commit{
+			Author:       signature,
+			Committer:    signature,
+			Message:      message,
+			TreeHash:     base.TreeHash,
+			ParentHashes: []plumbing.Hash{parent},
+		})
+		chain = append(chain, parent)
+	}
+
+	ahead, behind, err = aheadBehind(repo, chain[0], chain[2])
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), ahead)
+	assert.Equal(t, int64(2), behind)
+}
+
+func TestBranchAheadBehind(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	commit := renameFunctionFile(repo, "renamed.txt")
+
+	repoConfig := config.NewConfig()
+	repoConfig.Branches["main"] = &config.Branch{Name: "main", Remote: ".", Merge: head.Name()}
+	repoConfig.Branches["gone"] = &config.Branch{Name: "gone", Remote: ".", Merge: "refs/heads/unknown"}
+
+	ahead, behind, ok := branchAheadBehind(repo, repoConfig, "refs/heads/main", head.Hash())
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), ahead)
+	assert.Equal(t, int64(1), behind)
+
+	_, _, ok = branchAheadBehind(repo, repoConfig, "refs/heads/gone", commit.Hash)
+	assert.False(t, ok)
+
+	_, _, ok = branchAheadBehind(repo, repoConfig, "refs/heads/other", commit.Hash)
+	assert.False(t, ok)
+}
+
+func TestSelectRemotes(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"name", "url", "fetch_refspecs", "push_url"}
+
+	group, err := selectRemotes(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	_, _ = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/repo.git"}})
+
+	group, err = selectRemotes(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: "origin"},
+		ast.TextValue{Value: "https://example.com/repo.git"},
+		ast.TextValue{Value: "+refs/heads/*:refs/remotes/origin/*"},
+		ast.TextValue{Value: "https://example.com/repo.git"},
+	}, group.Rows[0].Values)
+}
+
+func TestRemotePushURL(t *testing.T) {
+	repoConfig := config.NewConfig()
+	remote := &config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/repo.git"}}
+	assert.Equal(t, "https://example.com/repo.git", remotePushURL(repoConfig, remote))
+
+	repoConfig.Raw.Section("remote").Subsection("origin").SetOption("pushurl", "ssh://example.com/repo.git")
+	assert.Equal(t, "ssh://example.com/repo.git", remotePushURL(repoConfig, remote))
+
+	assert.Equal(t, "", remotePushURL(repoConfig, &config.RemoteConfig{Name: "upstream"}))
 }
 
 func TestSelectDiffs(t *testing.T) {
@@ -203,6 +521,929 @@ func TestSelectTags(t *testing.T) {
 	assert.Equal(t, len(fieldsNames), len(group.Rows[0].Values))
 }
 
+func TestSelectTagsDetails(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, err := repo.Head()
+	assert.Nil(t, err)
+
+	tagger := object.Signature{Name: "tagger", Email: "tagger@example.com", When: time.Unix(1700000000, 0)}
+	annotated, err := repo.CreateTag("v1.0.0", head.Hash(), &git.CreateTagOptions{Tagger: &tagger, Message: "Release"})
+	assert.Nil(t, err)
+	_, err = repo.CreateTag("v0.0.2", head.Hash(), nil)
+	assert.Nil(t, err)
+
+	fieldsNames := []string{"name", "commit_id", "tag_object_id", "is_annotated", "tagger_name", "tagger_email",
+		"datetime", "message"}
+
+	group, err := selectTags(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+
+	// The tag created by newFunctionRepo depends on the git identity of the environment
+	rows := map[string]ast.Row{}
+	for _, row := range group.Rows {
+		rows[row.Values[0].AsText()] = row
+	}
+	assert.Equal(t, ast.Row{Values: []ast.Value{
+		ast.TextValue{Value: "v1.0.0"},
+		ast.TextValue{Value: head.Hash().String()},
+		ast.TextValue{Value: annotated.Hash().String()},
+		ast.BooleanValue{Value: true},
+		ast.TextValue{Value: "tagger"},
+		ast.TextValue{Value: "tagger@example.com"},
+		ast.DateTimeValue{Value: 1700000000},
+		ast.TextValue{Value: "Release\n"},
+	}}, rows["v1.0.0"])
+	assert.Equal(t, ast.Row{Values: []ast.Value{
+		ast.TextValue{Value: "v0.0.2"},
+		ast.TextValue{Value: head.Hash().String()},
+		ast.NullValue{},
+		ast.BooleanValue{Value: false},
+		ast.NullValue{},
+		ast.NullValue{},
+		ast.NullValue{},
+		ast.NullValue{},
+	}}, rows["v0.0.2"])
+}
+
+func TestPeelTag(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, err := repo.Head()
+	assert.Nil(t, err)
+
+	tagger := object.Signature{Name: "tagger", Email: "tagger@example.com", When: time.Unix(1700000000, 0)}
+	annotated, err := repo.CreateTag("v1.0.0", head.Hash(), &git.CreateTagOptions{Tagger: &tagger, Message: "Release"})
+	assert.Nil(t, err)
+
+	tag, commitId, err := peelTag(repo, annotated)
+	assert.Nil(t, err)
+	assert.Equal(t, annotated.Hash(), tag.Hash)
+	assert.Equal(t, head.Hash(), commitId)
+
+	// Tag of a tag
+	nested, err := repo.CreateTag("v1.0.0-nested", annotated.Hash(), &git.CreateTagOptions{
+		Tagger:  &tagger,
+		Message: "Nested tag",
+	})
+	assert.Nil(t, err)
+
+	tag, commitId, err = peelTag(repo, nested)
+	assert.Nil(t, err)
+	assert.Equal(t, nested.Hash(), tag.Hash)
+	assert.Equal(t, head.Hash(), commitId)
+
+	lightweight, err := repo.CreateTag("v0.0.2", head.Hash(), nil)
+	assert.Nil(t, err)
+
+	tag, commitId, err = peelTag(repo, lightweight)
+	assert.Nil(t, err)
+	assert.Nil(t, tag)
+	assert.Equal(t, head.Hash(), commitId)
+}
+
+func TestIsAnnotatedTagField(t *testing.T) {
+	assert.True(t, isAnnotatedTagField("tagger_name"))
+	assert.True(t, isAnnotatedTagField("message"))
+	assert.False(t, isAnnotatedTagField("name"))
+	assert.False(t, isAnnotatedTagField("commit_id"))
+}
+
+func TestSelectFiles(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"path", "name", "extension", "size", "mode", "is_binary", "revision"}
+
+	group, err := selectFiles(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: functionFile},
+		ast.TextValue{Value: functionFile},
+		ast.TextValue{Value: "txt"},
+		ast.IntegerValue{Value: 11},
+		ast.TextValue{Value: "100644"},
+		ast.BooleanValue{Value: false},
+		ast.TextValue{Value: "HEAD"},
+	}, group.Rows[0].Values)
+
+	// The files which don't match the path conditions are skipped
+	pathGlob := &ast.GlobExpression{
+		Input:   &ast.SymbolExpression{Value: "path"},
+		Pattern: &ast.StringExpression{Value: "*.go", ValueType: ast.StringValueText},
+	}
+
+	group, err = selectFiles(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, pathGlob))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+}
+
+func TestSelectBlame(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"path", "line_no", "line", "name", "revision"}
+	pathEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "path"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: functionFile, ValueType: ast.StringValueText},
+	}
+
+	group, err := selectBlame(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, pathEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: functionFile},
+		ast.IntegerValue{Value: 1},
+		ast.TextValue{Value: "hello world"},
+		ast.TextValue{Value: "name"},
+		ast.TextValue{Value: "HEAD"},
+	}, group.Rows[0].Values)
+
+	// The files which don't match the path conditions are not blamed
+	pathLike := &ast.LikeExpression{
+		Input:   &ast.SymbolExpression{Value: "path"},
+		Pattern: &ast.StringExpression{Value: "src/%", ValueType: ast.StringValueText},
+	}
+
+	group, err = selectBlame(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, pathLike))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	// Unknown revision
+	revisionEquals := &ast.ComparisonExpression{
+		Left:     &ast.StringExpression{Value: "unknown", ValueType: ast.StringValueText},
+		Operator: ast.COEqual,
+		Right:    &ast.SymbolExpression{Value: "revision"},
+	}
+
+	_, err = selectBlame(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, revisionEquals))
+	assert.Equal(t, "unknown revision unknown", err.Error())
+}
+
+func TestSelectDiffFiles(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"path", "old_path", "change_type", "insertions", "deletions", "is_binary"}
+
+	group, err := selectDiffFiles(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: functionFile},
+		ast.TextValue{Value: ""},
+		ast.TextValue{Value: "added"},
+		ast.IntegerValue{Value: 1},
+		ast.IntegerValue{Value: 0},
+		ast.BooleanValue{Value: false},
+	}, group.Rows[0].Values)
+
+	// The renamed files are detected
+	commit := renameFunctionFile(repo, "renamed.txt")
+	commitEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "commit_id"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: commit.Hash.String(), ValueType: ast.StringValueText},
+	}
+
+	group, err = selectDiffFiles(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, commitEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: "renamed.txt"},
+		ast.TextValue{Value: functionFile},
+		ast.TextValue{Value: "renamed"},
+		ast.IntegerValue{Value: 0},
+		ast.IntegerValue{Value: 0},
+		ast.BooleanValue{Value: false},
+	}, group.Rows[0].Values)
+
+	// The files which don't match the path conditions are skipped
+	pathEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "path"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: functionFile, ValueType: ast.StringValueText},
+	}
+
+	group, err = selectDiffFiles(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, pathEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, ast.TextValue{Value: "added"}, group.Rows[0].Values[2])
+}
+
+func TestSelectDiffHunks(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"path", "hunk_index", "old_start", "old_lines", "new_start", "new_lines", "kind", "content"}
+
+	group, err := selectDiffHunks(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: functionFile},
+		ast.IntegerValue{Value: 0},
+		ast.IntegerValue{Value: 0},
+		ast.IntegerValue{Value: 0},
+		ast.IntegerValue{Value: 1},
+		ast.IntegerValue{Value: 1},
+		ast.TextValue{Value: "header"},
+		ast.TextValue{Value: "@@ -0,0 +1 @@"},
+	}, group.Rows[0].Values)
+	assert.Equal(t, ast.TextValue{Value: "added"}, group.Rows[1].Values[6])
+	assert.Equal(t, ast.TextValue{Value: "hello world"}, group.Rows[1].Values[7])
+
+	// The files which don't match the path conditions are skipped
+	pathGlob := &ast.GlobExpression{
+		Input:   &ast.SymbolExpression{Value: "path"},
+		Pattern: &ast.StringExpression{Value: "*.go", ValueType: ast.StringValueText},
+	}
+
+	group, err = selectDiffHunks(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, pathGlob))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+}
+
+func TestHunkLineKind(t *testing.T) {
+	assert.Equal(t, "header", hunkLineKind(0, diffLine{}))
+	assert.Equal(t, "added", hunkLineKind(1, diffLine{operation: diff.Add}))
+	assert.Equal(t, "removed", hunkLineKind(1, diffLine{operation: diff.Delete}))
+}
+
+func TestCommitChanges(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	root, _ := repo.CommitObject(head.Hash())
+
+	changes, err := commitChanges(root)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, functionFile, changes[0].To.Name)
+
+	commit := renameFunctionFile(repo, "renamed.txt")
+
+	changes, err = commitChanges(commit)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(changes))
+	assert.Equal(t, functionFile, changes[0].From.Name)
+	assert.Equal(t, "renamed.txt", changes[0].To.Name)
+}
+
+func TestChangePaths(t *testing.T) {
+	tests := []struct {
+		change     *object.Change
+		path       string
+		oldPath    string
+		changeType string
+	}{
+		{&object.Change{To: object.ChangeEntry{Name: "a"}}, "a", "", "added"},
+		{&object.Change{From: object.ChangeEntry{Name: "a"}}, "a", "a", "deleted"},
+		{&object.Change{From: object.ChangeEntry{Name: "a"}, To: object.ChangeEntry{Name: "a"}}, "a", "a", "modified"},
+		{&object.Change{From: object.ChangeEntry{Name: "a"}, To: object.ChangeEntry{Name: "b"}}, "b", "a", "renamed"},
+	}
+
+	for _, test := range tests {
+		path, oldPath, changeType := changePaths(test.change)
+		assert.Equal(t, test.path, path)
+		assert.Equal(t, test.oldPath, oldPath)
+		assert.Equal(t, test.changeType, changeType)
+	}
+}
+
+func TestSelectReflog(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"ref", "index", "old_id", "new_id", "name", "email", "datetime", "message"}
+
+	group, err := selectReflog(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	_ = util.WriteFile(storer.Filesystem(), "logs/HEAD", []byte(reflogLine+"\n"), 0o644)
+	_ = util.WriteFile(storer.Filesystem(), "logs/refs/heads/main", []byte(reflogLine+"\n"+reflogLine+"\n"), 0o644)
+
+	group, err = selectReflog(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: "HEAD"},
+		ast.IntegerValue{Value: 0},
+		ast.TextValue{Value: reflogOldId},
+		ast.TextValue{Value: reflogNewId},
+		ast.TextValue{Value: "name"},
+		ast.TextValue{Value: "name@example.com"},
+		ast.DateTimeValue{Value: 1700000000},
+		ast.TextValue{Value: "commit (initial): Initial"},
+	}, group.Rows[0].Values)
+	assert.Equal(t, ast.IntegerValue{Value: 1}, group.Rows[2].Values[1])
+
+	// The references which don't match the ref conditions are skipped
+	refEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "ref"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: "HEAD", ValueType: ast.StringValueText},
+	}
+
+	group, err = selectReflog(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, refEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+}
+
+func TestSelectStashes(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"index", "commit_id", "message", "branch", "datetime", "files_changed"}
+
+	group, err := selectStashes(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	head, _ := repo.Head()
+	commit := renameFunctionFile(repo, "renamed.txt")
+	stashLines := head.Hash().String() + " " + commit.Hash.String() + " name <name@example.com> 1700000000 +0100\tWIP on main: 1234567 Adding\n" +
+		commit.Hash.String() + " " + reflogNewId + " name <name@example.com> 1700000100 +0100\tOn feature: Experiment\n"
+
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	_ = util.WriteFile(storer.Filesystem(), "logs/refs/stash", []byte(stashLines), 0o644)
+
+	group, err = selectStashes(&env, repo, fieldsNames, fieldsNames, nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 0},
+		ast.TextValue{Value: reflogNewId},
+		ast.TextValue{Value: "On feature: Experiment"},
+		ast.TextValue{Value: "feature"},
+		ast.DateTimeValue{Value: 1700000100},
+		ast.NullValue{},
+	}, group.Rows[0].Values)
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 1},
+		ast.TextValue{Value: commit.Hash.String()},
+		ast.TextValue{Value: "WIP on main: 1234567 Adding"},
+		ast.TextValue{Value: "main"},
+		ast.DateTimeValue{Value: 1700000000},
+		ast.IntegerValue{Value: 1},
+	}, group.Rows[1].Values)
+}
+
+func TestSelectStatus(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"path", "staging", "worktree", "is_untracked"}
+
+	group, err := selectStatus(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	_ = os.WriteFile(filepath.Join(functionRepo, functionFile), []byte("hello status"), 0o644)
+	_ = os.WriteFile(filepath.Join(functionRepo, "untracked.txt"), []byte("untracked"), 0o644)
+
+	group, err = selectStatus(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: functionFile},
+		ast.TextValue{Value: "unmodified"},
+		ast.TextValue{Value: "modified"},
+		ast.BooleanValue{Value: false},
+	}, group.Rows[0].Values)
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: "untracked.txt"},
+		ast.TextValue{Value: "untracked"},
+		ast.TextValue{Value: "untracked"},
+		ast.BooleanValue{Value: true},
+	}, group.Rows[1].Values)
+
+	// The bare repositories have no status
+	bareRepo, _ := git.PlainInit(functionRepo+".bare", true)
+	defer func() { _ = os.RemoveAll(functionRepo + ".bare") }()
+
+	group, err = selectStatus(&env, bareRepo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+}
+
+func TestStatusCodeName(t *testing.T) {
+	assert.Equal(t, "unmodified", statusCodeName(git.Unmodified))
+	assert.Equal(t, "untracked", statusCodeName(git.Untracked))
+	assert.Equal(t, "modified", statusCodeName(git.Modified))
+	assert.Equal(t, "added", statusCodeName(git.Added))
+	assert.Equal(t, "deleted", statusCodeName(git.Deleted))
+	assert.Equal(t, "renamed", statusCodeName(git.Renamed))
+	assert.Equal(t, "copied", statusCodeName(git.Copied))
+	assert.Equal(t, "unmerged", statusCodeName(git.UpdatedButUnmerged))
+}
+
+func storeObject(repo *git.Repository, o interface {
+	Encode(plumbing.EncodedObject) error
+}) plumbing.Hash {
+	encoded := repo.Storer.NewEncodedObject()
+	_ = o.Encode(encoded)
+	hash, _ := repo.Storer.SetEncodedObject(encoded)
+	return hash
+}
+
+func storeBlob(repo *git.Repository, content string) plumbing.Hash {
+	blob := repo.Storer.NewEncodedObject()
+	blob.SetType(plumbing.BlobObject)
+	writer, _ := blob.Writer()
+	_, _ = writer.Write([]byte(content))
+	_ = writer.Close()
+	hash, _ := repo.Storer.SetEncodedObject(blob)
+	return hash
+}
+
+func addSubmoduleCommit(repo *git.Repository, submoduleHash plumbing.Hash) *object.Commit {
+	blobHash := storeBlob(repo, "[submodule \"lib\"]\n\tpath = vendor/lib\n\turl = https://example.com/lib.git\n\tbranch = main\n")
+	vendorHash := storeObject(repo, &object.Tree{Entries: []object.TreeEntry{
+		{Name: "lib", Mode: filemode.Submodule, Hash: submoduleHash},
+	}})
+	treeHash := storeObject(repo, &object.Tree{Entries: []object.TreeEntry{
+		{Name: ".gitmodules", Mode: filemode.Regular, Hash: blobHash},
+		{Name: "vendor", Mode: filemode.Dir, Hash: vendorHash},
+	}})
+
+	head, _ := repo.Head()
+	signature := object.Signature{Name: "name", Email: "name@example.com", When: time.Now()}
+	commitHash := storeObject(repo, &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      "Adding submodule",
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{head.Hash()},
+	})
+
+	commit, _ := repo.CommitObject(commitHash)
+	return commit
+}
+
+func TestSelectSubmodules(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"name", "path", "url", "branch", "commit_id", "is_initialized", "revision"}
+
+	group, err := selectSubmodules(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	submoduleHash := plumbing.NewHash(reflogNewId)
+	commit := addSubmoduleCommit(repo, submoduleHash)
+	revisionEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "revision"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: commit.Hash.String(), ValueType: ast.StringValueText},
+	}
+
+	group, err = selectSubmodules(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, revisionEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.TextValue{Value: "lib"},
+		ast.TextValue{Value: "vendor/lib"},
+		ast.TextValue{Value: "https://example.com/lib.git"},
+		ast.TextValue{Value: "main"},
+		ast.TextValue{Value: submoduleHash.String()},
+		ast.BooleanValue{Value: false},
+		ast.TextValue{Value: commit.Hash.String()},
+	}, group.Rows[0].Values)
+
+	// The submodules of the repository configuration are initialized
+	repoConfig, _ := repo.Config()
+	repoConfig.Submodules["lib"] = &config.Submodule{Name: "lib", URL: "https://example.com/lib.git"}
+	_ = repo.SetConfig(repoConfig)
+
+	group, err = selectSubmodules(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, revisionEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ast.BooleanValue{Value: true}, group.Rows[0].Values[5])
+}
+
+func TestRevisionModules(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, _ := repo.Head()
+	root, _ := repo.CommitObject(head.Hash())
+
+	modules, err := revisionModules(root)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(modules.Submodules))
+
+	commit := addSubmoduleCommit(repo, plumbing.NewHash(reflogNewId))
+
+	modules, err = revisionModules(commit)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(modules.Submodules))
+	assert.Equal(t, "vendor/lib", modules.Submodules["lib"].Path)
+}
+
+func TestSelectNotes(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"notes_ref", "commit_id", "note"}
+
+	group, err := selectNotes(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	// The notes files can be split into directories by the first characters of the commit id
+	head, _ := repo.Head()
+	commitId := head.Hash().String()
+	fanoutHash := storeObject(repo, &object.Tree{Entries: []object.TreeEntry{
+		{Name: reflogNewId[2:], Mode: filemode.Regular, Hash: storeBlob(repo, "Build: passed\n")},
+	}})
+	entries := []object.TreeEntry{
+		{Name: reflogNewId[:2], Mode: filemode.Dir, Hash: fanoutHash},
+		{Name: commitId, Mode: filemode.Regular, Hash: storeBlob(repo, "Code-Review+2\n")},
+	}
+	// The tree entries are sorted by name and the directories are compared with a trailing slash
+	if commitId < reflogNewId[:2]+"/" {
+		entries[0], entries[1] = entries[1], entries[0]
+	}
+	treeHash := storeObject(repo, &object.Tree{Entries: entries})
+	signature := object.Signature{Name: "name", Email: "name@example.com", When: time.Now()}
+	notesHash := storeObject(repo, &object.Commit{Author: signature, Committer: signature, Message: "Notes added", TreeHash: treeHash})
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/notes/review", notesHash))
+
+	group, err = selectNotes(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(group.Rows))
+	assert.ElementsMatch(t, []ast.Row{
+		{Values: []ast.Value{
+			ast.TextValue{Value: "refs/notes/review"},
+			ast.TextValue{Value: commitId},
+			ast.TextValue{Value: "Code-Review+2\n"},
+		}},
+		{Values: []ast.Value{
+			ast.TextValue{Value: "refs/notes/review"},
+			ast.TextValue{Value: reflogNewId},
+			ast.TextValue{Value: "Build: passed\n"},
+		}},
+	}, group.Rows)
+
+	// The notes which don't match the commit id conditions are skipped
+	commitEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "commit_id"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: commitId, ValueType: ast.StringValueText},
+	}
+
+	group, err = selectNotes(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, commitEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+}
+
+func addGerritChange(repo *git.Repository) (*object.Commit, *object.Commit) {
+	head, _ := repo.Head()
+	first, _ := repo.CommitObject(head.Hash())
+
+	committer := object.Signature{Name: "uploader", Email: "uploader@example.com", When: time.Unix(1700000000, 0)}
+	secondHash := storeObject(repo, &object.Commit{
+		Author:       first.Author,
+		Committer:    committer,
+		Message:      "Adding file\n\nChange-Id: I" + reflogNewId + "\n",
+		TreeHash:     first.TreeHash,
+		ParentHashes: first.ParentHashes,
+	})
+	second, _ := repo.CommitObject(secondHash)
+
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/45/12345/1", first.Hash))
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/45/12345/2", second.Hash))
+
+	return first, second
+}
+
+func TestSelectGerritChanges(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"change_number", "change_id", "patchset_count", "current_patchset", "commit_id",
+		"uploader_name", "uploader_email", "datetime"}
+
+	group, err := selectGerritChanges(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	first, second := addGerritChange(repo)
+
+	group, err = selectGerritChanges(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 12345},
+		ast.TextValue{Value: "I" + reflogNewId},
+		ast.IntegerValue{Value: 2},
+		ast.IntegerValue{Value: 2},
+		ast.TextValue{Value: second.Hash.String()},
+		ast.TextValue{Value: "name"},
+		ast.TextValue{Value: "name@example.com"},
+		ast.DateTimeValue{Value: first.Committer.When.Unix()},
+	}, group.Rows[0].Values)
+
+	// The changes which don't match the change number conditions are skipped
+	changeEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "change_number"},
+		Operator: ast.COEqual,
+		Right:    &ast.NumberExpression{Value: ast.IntegerValue{Value: 1}},
+	}
+
+	group, err = selectGerritChanges(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, changeEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+}
+
+func TestSelectGerritPatchsets(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"change_number", "patchset_number", "commit_id", "change_id", "uploader_name",
+		"uploader_email", "datetime"}
+
+	first, second := addGerritChange(repo)
+
+	group, err := selectGerritPatchsets(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 2, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 12345},
+		ast.IntegerValue{Value: 1},
+		ast.TextValue{Value: first.Hash.String()},
+		ast.TextValue{Value: ""},
+		ast.TextValue{Value: "name"},
+		ast.TextValue{Value: "name@example.com"},
+		ast.DateTimeValue{Value: first.Committer.When.Unix()},
+	}, group.Rows[0].Values)
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 12345},
+		ast.IntegerValue{Value: 2},
+		ast.TextValue{Value: second.Hash.String()},
+		ast.TextValue{Value: "I" + reflogNewId},
+		ast.TextValue{Value: "uploader"},
+		ast.TextValue{Value: "uploader@example.com"},
+		ast.DateTimeValue{Value: 1700000000},
+	}, group.Rows[1].Values)
+}
+
+func addGerritMeta(repo *git.Repository) {
+	reviewer := object.Signature{Name: "reviewer", Email: "reviewer@gerrit", When: time.Unix(1700000000, 0)}
+	metaId := storeObject(repo, &object.Commit{
+		Author:    reviewer,
+		Committer: reviewer,
+		Message:   "Update patch set 2\n\nPatch-set: 2\nStatus: merged\nTopic: feature\nLabel: Code-Review=+2\nSubmitted-with: OK\n",
+	})
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/45/12345/meta", metaId))
+}
+
+func TestSelectGerritVotes(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"change_number", "patchset_number", "label", "value", "voter", "datetime"}
+
+	group, err := selectGerritVotes(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	_, _ = addGerritChange(repo)
+	addGerritMeta(repo)
+
+	group, err = selectGerritVotes(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 12345},
+		ast.IntegerValue{Value: 2},
+		ast.TextValue{Value: "Code-Review"},
+		ast.IntegerValue{Value: 2},
+		ast.TextValue{Value: "reviewer <reviewer@gerrit>"},
+		ast.DateTimeValue{Value: 1700000000},
+	}, group.Rows[0].Values)
+}
+
+func TestSelectGerritMeta(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"change_number", "status", "topic", "hashtags", "submitter"}
+
+	_, _ = addGerritChange(repo)
+
+	group, err := selectGerritMeta(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	addGerritMeta(repo)
+
+	group, err = selectGerritMeta(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{
+		ast.IntegerValue{Value: 12345},
+		ast.TextValue{Value: "merged"},
+		ast.TextValue{Value: "feature"},
+		ast.TextValue{Value: ""},
+		ast.TextValue{Value: "reviewer <reviewer@gerrit>"},
+	}, group.Rows[0].Values)
+}
+
+func TestSelectCommitTrailers(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	fieldsNames := []string{"commit_id", "key", "value", "position"}
+
+	group, err := selectCommitTrailers(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(group.Rows))
+
+	head, _ := repo.Head()
+	signature := object.Signature{Name: "name", Email: "name@example.com", When: time.Now()}
+	commitHash := storeObject(repo, &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      "Fix crash\n\nSigned-off-by: name <name@example.com>\nBug: 123\n",
+		TreeHash:     storeObject(repo, &object.Tree{}),
+		ParentHashes: []plumbing.Hash{head.Hash()},
+	})
+	commitId := commitHash.String()
+
+	group, err = selectCommitTrailers(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, nil))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []ast.Row{
+		{Values: []ast.Value{
+			ast.TextValue{Value: commitId},
+			ast.TextValue{Value: "Signed-off-by"},
+			ast.TextValue{Value: "name <name@example.com>"},
+			ast.IntegerValue{Value: 1},
+		}},
+		{Values: []ast.Value{
+			ast.TextValue{Value: commitId},
+			ast.TextValue{Value: "Bug"},
+			ast.TextValue{Value: "123"},
+			ast.IntegerValue{Value: 2},
+		}},
+	}, group.Rows)
+
+	keyEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "key"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: "Bug", ValueType: ast.StringValueText},
+	}
+
+	group, err = selectCommitTrailers(&env, repo, fieldsNames, fieldsNames, nil, newTableFilter(&env, keyEquals))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, ast.TextValue{Value: "123"}, group.Rows[0].Values[2])
+}
+
+func TestRevisionCommit(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.0.0", head.Hash(), nil)
+	require.NoError(t, err)
+
+	revision, commit, err := revisionCommit(repo, newTableFilter(&env, nil))
+	require.NoError(t, err)
+	require.NotNil(t, commit)
+	assert.Equal(t, "HEAD", revision)
+	assert.Equal(t, head.Hash(), commit.Hash)
+
+	revisionEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "revision"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: "v1.0.0", ValueType: ast.StringValueText},
+	}
+
+	revision, commit, err = revisionCommit(repo, newTableFilter(&env, revisionEquals))
+	require.NoError(t, err)
+	require.NotNil(t, commit)
+	assert.Equal(t, "v1.0.0", revision)
+	assert.Equal(t, head.Hash(), commit.Hash)
+
+	// An unknown revision is an error
+	revisionEquals.Right = &ast.StringExpression{Value: "no-such-tag", ValueType: ast.StringValueText}
+	_, _, err = revisionCommit(repo, newTableFilter(&env, revisionEquals))
+	assert.Equal(t, "unknown revision no-such-tag", err.Error())
+
+	// A repository without commits has no revision commit
+	emptyRepo, _ := git.Init(memory.NewStorage(), nil)
+	revision, commit, err = revisionCommit(emptyRepo, newTableFilter(&env, nil))
+	assert.Nil(t, err)
+	assert.Equal(t, "HEAD", revision)
+	assert.Nil(t, commit)
+}
+
 func TestSelectValues(t *testing.T) {
 	env := ast.Environment{
 		Globals:      map[string]ast.Value{},
@@ -294,4 +1535,16 @@ CC: R. E. Viewer <reviewer@example.com>`
 
 	ret = GetChangeIdFromCommitMessageFooter(commitMessage)
 	assert.NotEqual(t, changeId, ret)
+
+	// The Change-Id is a trailer only if its paragraph is a trailers block like for the trailer function
+	commitMessage = "Fix crash\n\nSee the linked issue for details\nChange-Id: " + changeId + "\n"
+	assert.Equal(t, "", GetChangeIdFromCommitMessageFooter(commitMessage))
+	assert.Equal(t, "", ast.GetTrailerValue(commitMessage, changeIdKey))
+
+	commitMessage = "Fix crash\n\nSee the linked issue for details\n\nChange-Id:  " + changeId + "  \n\n"
+	assert.Equal(t, changeId, GetChangeIdFromCommitMessageFooter(commitMessage))
+	assert.Equal(t, changeId, ast.GetTrailerValue(commitMessage, changeIdKey))
+
+	// The subject is never a trailer
+	assert.Equal(t, "", GetChangeIdFromCommitMessageFooter("Change-Id: "+changeId+"\n"))
 }
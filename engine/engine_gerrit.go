@@ -0,0 +1,258 @@
+package engine
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/ggql/ggql/ast"
+)
+
+var (
+	// https://gerrit-review.googlesource.com/Documentation/concept-refs-for-namespaces.html
+	gerritChangeRefPattern = regexp.MustCompile(`^refs/changes/\d{2}/(\d+)/(\d+|meta)$`)
+)
+
+// gerritPatchset is a patch set reference `refs/changes/NN/<change>/<patch set>` of a Gerrit change
+type gerritPatchset struct {
+	changeNumber   int64
+	patchsetNumber int64
+	commitId       plumbing.Hash
+}
+
+// gerritChange is the patch sets of a Gerrit change sorted by their numbers
+type gerritChange struct {
+	changeNumber int64
+	patchsets    []gerritPatchset
+	metaId       plumbing.Hash
+}
+
+// parseGerritChangeRef returns the change number and the patch set number of the change reference, the patch set
+// number of the NoteDb meta reference is 0
+func parseGerritChangeRef(name plumbing.ReferenceName) (int64, int64, bool) {
+	matches := gerritChangeRefPattern.FindStringSubmatch(name.String())
+	if matches == nil {
+		return 0, 0, false
+	}
+
+	changeNumber, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if matches[2] == "meta" {
+		return changeNumber, 0, true
+	}
+
+	patchsetNumber, err := strconv.ParseInt(matches[2], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return changeNumber, patchsetNumber, true
+}
+
+// gerritChanges returns the changes of the `refs/changes/*` references sorted by their numbers, the changes may have
+// no patch sets or no meta reference
+func gerritChanges(repo *git.Repository) ([]gerritChange, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	changesIndexes := make(map[int64]int)
+	var changes []gerritChange
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		changeNumber, patchsetNumber, ok := parseGerritChangeRef(ref.Name())
+		if !ok {
+			return nil
+		}
+
+		changeIndex, ok := changesIndexes[changeNumber]
+		if !ok {
+			changeIndex = len(changes)
+			changesIndexes[changeNumber] = changeIndex
+			changes = append(changes, gerritChange{changeNumber: changeNumber})
+		}
+
+		change := &changes[changeIndex]
+		if patchsetNumber == 0 {
+			change.metaId = ref.Hash()
+			return nil
+		}
+
+		change.patchsets = append(change.patchsets, gerritPatchset{
+			changeNumber:   changeNumber,
+			patchsetNumber: patchsetNumber,
+			commitId:       ref.Hash(),
+		})
+		return nil
+	})
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].changeNumber < changes[j].changeNumber
+	})
+	for _, change := range changes {
+		sort.Slice(change.patchsets, func(i, j int) bool {
+			return change.patchsets[i].patchsetNumber < change.patchsets[j].patchsetNumber
+		})
+	}
+
+	return changes, err
+}
+
+// gerritVote is the current vote of a voter on a label of a Gerrit change
+type gerritVote struct {
+	patchsetNumber int64
+	label          string
+	value          int64
+	voter          string
+	datetime       int64
+}
+
+// gerritMeta is the state of a Gerrit change stored in the NoteDb meta reference
+type gerritMeta struct {
+	status    string
+	topic     string
+	hashtags  string
+	submitter string
+	votes     []gerritVote
+}
+
+// readGerritMeta replays the commits of the NoteDb meta reference from the oldest one
+// https://gerrit.googlesource.com/gerrit/+/refs/heads/master/java/com/google/gerrit/server/notedb/ChangeNotesParser.java
+func readGerritMeta(repo *git.Repository, metaId plumbing.Hash) (gerritMeta, error) {
+	var commits []*object.Commit
+	commit, err := repo.CommitObject(metaId)
+	for err == nil {
+		commits = append(commits, commit)
+		if commit.NumParents() == 0 {
+			break
+		}
+		commit, err = commit.Parent(0)
+	}
+	if err != nil {
+		return gerritMeta{}, err
+	}
+
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return parseGerritMeta(commits), nil
+}
+
+// parseGerritMeta returns the change state after the updates of the NoteDb commits, the latest vote of a voter on a
+// label of a patch set replaces its previous votes on this patch set and the removed votes are dropped
+func parseGerritMeta(commits []*object.Commit) gerritMeta {
+	var meta gerritMeta
+	votesIndexes := make(map[string]int)
+	var votes []*gerritVote
+
+	var patchsetNumber int64
+	for _, commit := range commits {
+		author := gerritIdent(commit.Author.Name, commit.Author.Email)
+		for _, trailer := range ast.ParseTrailers(commit.Message) {
+			switch trailer.Key {
+			case "Patch-set":
+				// The patch set number can be followed by its state like `1 (PUBLISHED)`
+				value, _, _ := strings.Cut(trailer.Value, " ")
+				if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+					patchsetNumber = number
+				}
+			case "Status":
+				meta.status = strings.ToLower(trailer.Value)
+			case "Topic":
+				meta.topic = trailer.Value
+			case "Hashtags":
+				meta.hashtags = trailer.Value
+			case "Submitted-with":
+				meta.submitter = author
+			case "Label":
+				label, value, voter, isRemoved, ok := parseGerritLabel(trailer.Value)
+				if !ok {
+					continue
+				}
+				if voter == "" {
+					voter = author
+				}
+
+				key := strconv.FormatInt(patchsetNumber, 10) + "\n" + label + "\n" + voter
+				if isRemoved {
+					if index, ok := votesIndexes[key]; ok {
+						votes[index] = nil
+						delete(votesIndexes, key)
+					}
+					continue
+				}
+
+				vote := &gerritVote{
+					patchsetNumber: patchsetNumber,
+					label:          label,
+					value:          value,
+					voter:          voter,
+					datetime:       commit.Author.When.Unix(),
+				}
+				if index, ok := votesIndexes[key]; ok {
+					votes[index] = vote
+				} else {
+					votesIndexes[key] = len(votes)
+					votes = append(votes, vote)
+				}
+			}
+		}
+	}
+
+	for _, vote := range votes {
+		if vote != nil {
+			meta.votes = append(meta.votes, *vote)
+		}
+	}
+	return meta
+}
+
+// parseGerritLabel parses the `Label` footer like `Code-Review=+2`, `Code-Review=+2, <uuid> Name <id@server>` or
+// `-Code-Review Name <id@server>` for a removed vote, the voter is empty if it is the author of the update
+func parseGerritLabel(footer string) (string, int64, string, bool, bool) {
+	fields := strings.Fields(footer)
+	if len(fields) == 0 {
+		return "", 0, "", false, false
+	}
+
+	vote, voterFields := fields[0], fields[1:]
+	if strings.HasSuffix(vote, ",") {
+		// The vote uuid is before the voter
+		vote = strings.TrimSuffix(vote, ",")
+		if len(voterFields) > 0 {
+			voterFields = voterFields[1:]
+		}
+	}
+	voter := strings.Join(voterFields, " ")
+
+	if label, ok := strings.CutPrefix(vote, "-"); ok {
+		return label, 0, voter, true, label != ""
+	}
+
+	label, value, ok := strings.Cut(vote, "=")
+	if !ok || label == "" {
+		return "", 0, "", false, false
+	}
+
+	number, err := strconv.ParseInt(strings.TrimPrefix(value, "+"), 10, 64)
+	if err != nil {
+		return "", 0, "", false, false
+	}
+	return label, number, voter, false, true
+}
+
+// gerritIdent returns the account identity like `Name <id@server>`
+func gerritIdent(name, email string) string {
+	return name + " <" + email + ">"
+}
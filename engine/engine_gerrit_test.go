@@ -0,0 +1,137 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGerritChangeRef(t *testing.T) {
+	changeNumber, patchsetNumber, ok := parseGerritChangeRef("refs/changes/45/12345/3")
+	assert.True(t, ok)
+	assert.Equal(t, int64(12345), changeNumber)
+	assert.Equal(t, int64(3), patchsetNumber)
+
+	changeNumber, patchsetNumber, ok = parseGerritChangeRef("refs/changes/45/12345/meta")
+	assert.True(t, ok)
+	assert.Equal(t, int64(12345), changeNumber)
+	assert.Equal(t, int64(0), patchsetNumber)
+
+	_, _, ok = parseGerritChangeRef("refs/changes/45/12345/robot-comments")
+	assert.False(t, ok)
+
+	_, _, ok = parseGerritChangeRef("refs/changes/45/12345")
+	assert.False(t, ok)
+
+	_, _, ok = parseGerritChangeRef("refs/heads/main")
+	assert.False(t, ok)
+}
+
+func TestGerritChanges(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	changes, err := gerritChanges(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(changes))
+
+	head, _ := repo.Head()
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/45/12345/2", head.Hash()))
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/45/12345/1", head.Hash()))
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/01/101/1", head.Hash()))
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/changes/02/102/meta", head.Hash()))
+
+	changes, err = gerritChanges(repo)
+	assert.Nil(t, err)
+	assert.Equal(t, []gerritChange{
+		{changeNumber: 101, patchsets: []gerritPatchset{{101, 1, head.Hash()}}},
+		{changeNumber: 102, metaId: head.Hash()},
+		{changeNumber: 12345, patchsets: []gerritPatchset{{12345, 1, head.Hash()}, {12345, 2, head.Hash()}}},
+	}, changes)
+}
+
+func gerritMetaCommit(name string, when int64, message string) *object.Commit {
+	author := object.Signature{Name: name, Email: name + "@gerrit", When: time.Unix(when, 0)}
+	return &object.Commit{Author: author, Committer: author, Message: message}
+}
+
+func TestReadGerritMeta(t *testing.T) {
+	repo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	first := storeObject(repo, gerritMetaCommit("owner", 1, "Create change\n\nPatch-set: 1\nStatus: new\n"))
+	second := gerritMetaCommit("reviewer", 2, "Update patch set 1\n\nPatch-set: 1\nLabel: Code-Review=+2\n")
+	second.ParentHashes = []plumbing.Hash{first}
+	metaId := storeObject(repo, second)
+
+	meta, err := readGerritMeta(repo, metaId)
+	assert.Nil(t, err)
+	assert.Equal(t, "new", meta.status)
+	assert.Equal(t, []gerritVote{{1, "Code-Review", 2, "reviewer <reviewer@gerrit>", 2}}, meta.votes)
+
+	_, err = readGerritMeta(repo, plumbing.ZeroHash)
+	assert.NotNil(t, err)
+}
+
+func TestParseGerritMeta(t *testing.T) {
+	commits := []*object.Commit{
+		gerritMetaCommit("owner", 1, "Create change\n\nPatch-set: 1\nStatus: new\nTopic: feature\nHashtags: a,b\n"),
+		gerritMetaCommit("reviewer", 2, "Update patch set 1\n\nPatch-set: 1\nLabel: Code-Review=-1\nLabel: Verified=+1 ci <ci@gerrit>\n"),
+		gerritMetaCommit("owner", 3, "Upload patch set 2\n\nPatch-set: 2 (PUBLISHED)\n"),
+		gerritMetaCommit("reviewer", 4, "Update patch set 2\n\nPatch-set: 2\nLabel: Code-Review=+2, 3f2a9c reviewer <reviewer@gerrit>\n"),
+		gerritMetaCommit("ci", 5, "Update patch set 2\n\nPatch-set: 2\nLabel: Verified=+1\n"),
+		gerritMetaCommit("ci", 6, "Update patch set 2\n\nPatch-set: 2\nLabel: -Verified\n"),
+		gerritMetaCommit("reviewer", 7, "Update patch set 2\n\nPatch-set: 2\nLabel: Code-Review=+1\n"),
+		gerritMetaCommit("reviewer", 8, "Update patch set 2\n\nPatch-set: 2\nLabel: Code-Review=+2\n"),
+		gerritMetaCommit("submitter", 9, "Update patch set 2\n\nPatch-set: 2\nStatus: merged\nSubmitted-with: OK\nSubmitted-with: OK: Code-Review: reviewer <reviewer@gerrit>\n"),
+	}
+
+	meta := parseGerritMeta(commits)
+	assert.Equal(t, "merged", meta.status)
+	assert.Equal(t, "feature", meta.topic)
+	assert.Equal(t, "a,b", meta.hashtags)
+	assert.Equal(t, "submitter <submitter@gerrit>", meta.submitter)
+
+	// The votes of each patch set are kept, the later votes of a voter on a patch set replace the earlier ones
+	assert.Equal(t, []gerritVote{
+		{1, "Code-Review", -1, "reviewer <reviewer@gerrit>", 2},
+		{1, "Verified", 1, "ci <ci@gerrit>", 2},
+		{2, "Code-Review", 2, "reviewer <reviewer@gerrit>", 8},
+	}, meta.votes)
+}
+
+func TestParseGerritLabel(t *testing.T) {
+	tests := []struct {
+		footer    string
+		label     string
+		value     int64
+		voter     string
+		isRemoved bool
+		ok        bool
+	}{
+		{"Code-Review=+2", "Code-Review", 2, "", false, true},
+		{"Code-Review=-1 Name <1000@gerrit>", "Code-Review", -1, "Name <1000@gerrit>", false, true},
+		{"Code-Review=0, 3f2a9c Name <1000@gerrit>", "Code-Review", 0, "Name <1000@gerrit>", false, true},
+		{"-Verified", "Verified", 0, "", true, true},
+		{"-Verified Name <1000@gerrit>", "Verified", 0, "Name <1000@gerrit>", true, true},
+		{"Code-Review=x", "", 0, "", false, false},
+		{"Code-Review", "", 0, "", false, false},
+		{"", "", 0, "", false, false},
+	}
+
+	for _, test := range tests {
+		label, value, voter, isRemoved, ok := parseGerritLabel(test.footer)
+		assert.Equal(t, test.label, label, test.footer)
+		assert.Equal(t, test.value, value, test.footer)
+		assert.Equal(t, test.voter, voter, test.footer)
+		assert.Equal(t, test.isRemoved, isRemoved, test.footer)
+		assert.Equal(t, test.ok, ok, test.footer)
+	}
+}
+
+func TestGerritIdent(t *testing.T) {
+	assert.Equal(t, "Name <1000@gerrit>", gerritIdent("Name", "1000@gerrit"))
+}
@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/ggql/ggql/ast"
+)
+
+type joinedTable struct {
+	reference string
+	name      string
+	derived   *ast.DerivedTable
+	isLeft    bool
+}
+
+type joinedRows struct {
+	titles []string
+	rows   [][]ast.Value
+}
+
+func executeJoinSelectStatement(
+	env *ast.Environment,
+	statement *ast.SelectStatement,
+	repo *git.Repository,
+	gitqlObject *ast.GitQLObject,
+	hiddenSelections []string,
+) error {
+	tables := []joinedTable{{reference: statement.TableReference(), name: statement.TableName, derived: statement.DerivedTable}}
+	for index := range statement.Joins {
+		join := &statement.Joins[index]
+		tables = append(tables, joinedTable{reference: join.TableReference(), name: join.TableName, derived: join.DerivedTable, isLeft: join.Kind == ast.LeftJoin})
+	}
+
+	usedNames := make([]string, 0, len(hiddenSelections)+len(statement.FieldsNames))
+	usedNames = append(usedNames, hiddenSelections...)
+	usedNames = append(usedNames, statement.FieldsNames...)
+
+	result, err := selectJoinedTable(env, repo, tables[0], usedNames, statement.Condition)
+	if err != nil {
+		return err
+	}
+
+	for index, join := range statement.Joins {
+		right, err := selectJoinedTable(env, repo, tables[index+1], usedNames, statement.Condition)
+		if err != nil {
+			return err
+		}
+
+		result, err = joinRows(env, result, right, join.Kind, join.Condition)
+		if err != nil {
+			return err
+		}
+	}
+
+	var titles []string
+	for _, hidden := range hiddenSelections {
+		if !contains(statement.FieldsNames, hidden) {
+			titles = append(titles, hidden)
+		}
+	}
+	hiddenCount := len(titles)
+
+	group := ast.Group{Rows: make([]ast.Row, 0, len(result.rows))}
+	for _, joinedValues := range result.rows {
+		values := make([]ast.Value, 0, hiddenCount+len(statement.FieldsNames))
+		for _, hidden := range titles {
+			values = append(values, joinedSymbolValue(hidden, result.titles, joinedValues))
+		}
+
+		for index, fieldName := range statement.FieldsNames {
+			if index >= len(statement.FieldsValues) {
+				values = append(values, joinedSymbolValue(fieldName, result.titles, joinedValues))
+				continue
+			}
+
+			if symbol, ok := statement.FieldsValues[index].(*ast.SymbolExpression); ok {
+				values = append(values, joinedSymbolValue(symbol.Value, result.titles, joinedValues))
+				continue
+			}
+
+			value, err := EvaluateExpression(env, statement.FieldsValues[index], result.titles, joinedValues)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+
+		group.Rows = append(group.Rows, ast.Row{Values: values})
+	}
+
+	if len(gitqlObject.Titles) == 0 {
+		gitqlObject.Titles = append(gitqlObject.Titles, titles...)
+		gitqlObject.Titles = append(gitqlObject.Titles, statement.FieldsNames...)
+	}
+
+	if gitqlObject.IsEmpty() {
+		gitqlObject.Groups = append(gitqlObject.Groups, group)
+	} else {
+		gitqlObject.Groups[0].Rows = append(gitqlObject.Groups[0].Rows, group.Rows...)
+	}
+
+	return nil
+}
+
+// selectJoinedTable selects the table fields used by the query, the titles are qualified with the table reference,
+// the `WHERE` conditions on the table fields are used like in a single table query except for the left joined tables
+// which must keep the rows that can't match the conditions
+func selectJoinedTable(
+	env *ast.Environment,
+	repo *git.Repository,
+	table joinedTable,
+	usedNames []string,
+	condition ast.Expression,
+) (joinedRows, error) {
+	prefix := table.reference + "."
+
+	var fieldsNames []string
+	for _, name := range usedNames {
+		fieldName, ok := strings.CutPrefix(name, prefix)
+		if ok && !contains(fieldsNames, fieldName) {
+			fieldsNames = append(fieldsNames, fieldName)
+		}
+	}
+
+	// At least one field is needed to know how many rows the table has
+	if len(fieldsNames) == 0 && table.derived != nil {
+		fieldsNames = append(fieldsNames, table.derived.FieldsNames[0])
+	} else if len(fieldsNames) == 0 {
+		fieldsNames = append(fieldsNames, ast.TablesFieldsNames[table.name][0])
+	}
+
+	fieldsValues := make([]ast.Expression, 0, len(fieldsNames))
+	titles := make([]string, 0, len(fieldsNames))
+	for _, fieldName := range fieldsNames {
+		fieldsValues = append(fieldsValues, &ast.SymbolExpression{Value: fieldName})
+		titles = append(titles, prefix+fieldName)
+	}
+
+	var group *ast.Group
+	var err error
+	if table.derived != nil {
+		group, err = selectDerivedTable(env, repo, table.derived, fieldsNames, fieldsNames, fieldsValues)
+	} else {
+		filter := newJoinedTableFilter(env, condition, table.reference)
+		if table.isLeft {
+			// The revision selects the rows of the table, it can't be ignored
+			if _, ok := filter.Value("revision"); ok {
+				return joinedRows{}, fmt.Errorf("revision of the left joined table %s can't be filtered, use an inner join", table.reference)
+			}
+			filter = newTableFilter(env, nil)
+		}
+
+		group, err = selectTableObjects(env, repo, table.name, fieldsNames, fieldsNames, fieldsValues, filter)
+	}
+	if err != nil {
+		return joinedRows{}, err
+	}
+
+	rows := make([][]ast.Value, 0, len(group.Rows))
+	for _, row := range group.Rows {
+		rows = append(rows, row.Values)
+	}
+
+	return joinedRows{titles: titles, rows: rows}, nil
+}
+
+// joinRows combines each left row with the right rows matching the condition, equality conditions between a left
+// and a right field are matched using a hash table instead of evaluating the condition for each pair of rows
+func joinRows(
+	env *ast.Environment,
+	left joinedRows,
+	right joinedRows,
+	kind ast.JoinKind,
+	condition ast.Expression,
+) (joinedRows, error) {
+	titles := make([]string, 0, len(left.titles)+len(right.titles))
+	titles = append(titles, left.titles...)
+	titles = append(titles, right.titles...)
+
+	leftKey, rightKey := equalityJoinKeys(env, condition, left.titles, right.titles)
+
+	var buckets map[string][]int
+	if leftKey != -1 {
+		buckets = make(map[string][]int)
+		for index, row := range right.rows {
+			key := row[rightKey].Fmt()
+			buckets[key] = append(buckets[key], index)
+		}
+	}
+
+	allRightRows := make([]int, len(right.rows))
+	for index := range allRightRows {
+		allRightRows[index] = index
+	}
+
+	var rows [][]ast.Value
+	for _, leftRow := range left.rows {
+		candidates := allRightRows
+		if buckets != nil {
+			candidates = buckets[leftRow[leftKey].Fmt()]
+		}
+
+		matched := false
+		for _, index := range candidates {
+			values := make([]ast.Value, 0, len(titles))
+			values = append(values, leftRow...)
+			values = append(values, right.rows[index]...)
+
+			isMatch, err := EvaluateExpression(env, condition, titles, values)
+			if err != nil {
+				return joinedRows{}, err
+			}
+
+			if isMatch.AsBool() {
+				matched = true
+				rows = append(rows, values)
+			}
+		}
+
+		if !matched && kind == ast.LeftJoin {
+			values := make([]ast.Value, 0, len(titles))
+			values = append(values, leftRow...)
+			for range right.titles {
+				values = append(values, ast.NullValue{})
+			}
+			rows = append(rows, values)
+		}
+	}
+
+	return joinedRows{titles: titles, rows: rows}, nil
+}
+
+// equalityJoinKeys returns the left and right fields indexes if the condition is an equality between a left field
+// and a right field with the same type, otherwise it returns -1, -1
+func equalityJoinKeys(env *ast.Environment, condition ast.Expression, leftTitles, rightTitles []string) (int, int) {
+	comparison, ok := condition.(*ast.ComparisonExpression)
+	if !ok || comparison.Operator != ast.COEqual {
+		return -1, -1
+	}
+
+	first, firstOk := comparison.Left.(*ast.SymbolExpression)
+	second, secondOk := comparison.Right.(*ast.SymbolExpression)
+	if !firstOk || !secondOk {
+		return -1, -1
+	}
+
+	firstType := first.ExprType(env)
+	if firstType.IsAny() || !firstType.Equal(second.ExprType(env)) {
+		return -1, -1
+	}
+
+	if leftKey, rightKey := indexOf(leftTitles, first.Value), indexOf(rightTitles, second.Value); leftKey != -1 && rightKey != -1 {
+		return leftKey, rightKey
+	}
+
+	if leftKey, rightKey := indexOf(leftTitles, second.Value), indexOf(rightTitles, first.Value); leftKey != -1 && rightKey != -1 {
+		return leftKey, rightKey
+	}
+
+	return -1, -1
+}
+
+// joinedSymbolValue returns the value of the field or null for the names which are not fields of the joined tables
+func joinedSymbolValue(name string, titles []string, values []ast.Value) ast.Value {
+	if index := indexOf(titles, name); index != -1 {
+		return values[index]
+	}
+	return ast.NullValue{}
+}
@@ -0,0 +1,167 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+)
+
+func TestExecuteJoinSelectStatement(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	env.Define("r.full_name", ast.Text{})
+	env.Define("b.name", ast.Text{})
+
+	// SELECT r.full_name, b.commit_count FROM refs r LEFT JOIN branches b ON r.full_name = b.name
+	statement := &ast.SelectStatement{
+		TableName:  "refs",
+		TableAlias: "r",
+		Joins: []ast.Join{
+			{
+				Kind:       ast.LeftJoin,
+				TableName:  "branches",
+				TableAlias: "b",
+				Condition: &ast.ComparisonExpression{
+					Left:     &ast.SymbolExpression{Value: "r.full_name"},
+					Operator: ast.COEqual,
+					Right:    &ast.SymbolExpression{Value: "b.name"},
+				},
+			},
+		},
+		FieldsNames: []string{"r.full_name", "b.commit_count"},
+		FieldsValues: []ast.Expression{
+			&ast.SymbolExpression{Value: "r.full_name"},
+			&ast.SymbolExpression{Value: "b.commit_count"},
+		},
+		AliasTable: map[string]string{},
+	}
+
+	gitqlObject := ast.GitQLObject{}
+	err := executeSelectStatement(&env, statement, repo, &gitqlObject, []string{"b.name"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b.name", "r.full_name", "b.commit_count"}, gitqlObject.Titles)
+
+	counts := make(map[string]ast.Value)
+	for _, row := range gitqlObject.Groups[0].Rows {
+		counts[row.Values[1].AsText()] = row.Values[2]
+	}
+	assert.Equal(t, ast.IntegerValue{Value: 1}, counts["refs/heads/master"])
+	assert.Equal(t, ast.IntegerValue{Value: 1}, counts["refs/heads/feature"])
+	assert.Equal(t, ast.NullValue{}, counts["HEAD"])
+}
+
+func TestSelectJoinedTable(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+
+	rows, err := selectJoinedTable(&env, repo, joinedTable{reference: "c", name: "commits"}, []string{"c.title", "b.name", "c.name", "c.title"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c.title", "c.name"}, rows.titles)
+	assert.Equal(t, 1, len(rows.rows))
+	assert.Equal(t, "name", rows.rows[0][1].AsText())
+
+	// Tables without used fields still select their rows
+	rows, err = selectJoinedTable(&env, repo, joinedTable{reference: "b", name: "branches"}, []string{"c.title"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b.name"}, rows.titles)
+	assert.Equal(t, 2, len(rows.rows))
+	// The revision of the joined table is selected by the query condition
+	revisionEquals := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "f.revision"},
+		Operator: ast.COEqual,
+		Right:    &ast.StringExpression{Value: "feature", ValueType: ast.StringValueText},
+	}
+
+	rows, err = selectJoinedTable(&env, repo, joinedTable{reference: "f", name: "files"}, []string{"f.path", "f.revision"}, revisionEquals)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows.rows))
+	assert.Equal(t, []ast.Value{ast.TextValue{Value: mutationFile}, ast.TextValue{Value: "feature"}}, rows.rows[0])
+
+	// The revision of a left joined table can't be filtered
+	_, err = selectJoinedTable(&env, repo, joinedTable{reference: "f", name: "files", isLeft: true}, []string{"f.path"}, revisionEquals)
+	assert.NotNil(t, err)
+}
+
+func TestJoinRows(t *testing.T) {
+	env := newMutationEnv()
+	env.Define("a.id", ast.Integer{})
+	env.Define("b.id", ast.Integer{})
+
+	left := joinedRows{
+		titles: []string{"a.id"},
+		rows:   [][]ast.Value{{ast.IntegerValue{Value: 1}}, {ast.IntegerValue{Value: 2}}},
+	}
+	right := joinedRows{
+		titles: []string{"b.id"},
+		rows:   [][]ast.Value{{ast.IntegerValue{Value: 2}}, {ast.IntegerValue{Value: 2}}, {ast.IntegerValue{Value: 3}}},
+	}
+
+	equal := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "b.id"},
+		Operator: ast.COEqual,
+		Right:    &ast.SymbolExpression{Value: "a.id"},
+	}
+
+	joined, err := joinRows(&env, left, right, ast.InnerJoin, equal)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a.id", "b.id"}, joined.titles)
+	assert.Equal(t, 2, len(joined.rows))
+
+	joined, err = joinRows(&env, left, right, ast.LeftJoin, equal)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(joined.rows))
+	assert.Equal(t, []ast.Value{ast.IntegerValue{Value: 1}, ast.NullValue{}}, joined.rows[0])
+
+	// Conditions which are not equalities are evaluated for each pair of rows
+	greater := &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "b.id"},
+		Operator: ast.COGreater,
+		Right:    &ast.SymbolExpression{Value: "a.id"},
+	}
+
+	joined, err = joinRows(&env, left, right, ast.InnerJoin, greater)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(joined.rows))
+}
+
+func TestEqualityJoinKeys(t *testing.T) {
+	env := newMutationEnv()
+	env.Define("a.id", ast.Integer{})
+	env.Define("b.id", ast.Integer{})
+	env.Define("b.name", ast.Text{})
+
+	leftKey, rightKey := equalityJoinKeys(&env, &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "a.id"},
+		Operator: ast.COEqual,
+		Right:    &ast.SymbolExpression{Value: "b.id"},
+	}, []string{"a.id"}, []string{"b.name", "b.id"})
+	assert.Equal(t, 0, leftKey)
+	assert.Equal(t, 1, rightKey)
+
+	// Fields with different types can be equal after casting
+	leftKey, rightKey = equalityJoinKeys(&env, &ast.ComparisonExpression{
+		Left:     &ast.SymbolExpression{Value: "a.id"},
+		Operator: ast.COEqual,
+		Right:    &ast.SymbolExpression{Value: "b.name"},
+	}, []string{"a.id"}, []string{"b.name", "b.id"})
+	assert.Equal(t, -1, leftKey)
+	assert.Equal(t, -1, rightKey)
+
+	leftKey, rightKey = equalityJoinKeys(&env, &ast.BooleanExpression{IsTrue: true}, []string{"a.id"}, []string{"b.id"})
+	assert.Equal(t, -1, leftKey)
+	assert.Equal(t, -1, rightKey)
+}
+
+func TestJoinedSymbolValue(t *testing.T) {
+	titles := []string{"a.id"}
+	values := []ast.Value{ast.IntegerValue{Value: 1}}
+
+	assert.Equal(t, ast.IntegerValue{Value: 1}, joinedSymbolValue("a.id", titles, values))
+	assert.Equal(t, ast.NullValue{}, joinedSymbolValue("b.id", titles, values))
+}
@@ -0,0 +1,729 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+
+	"github.com/ggql/ggql/ast"
+)
+
+const (
+	fieldCommitId = "commit_id"
+	fieldFullName = "full_name"
+	fieldMessage  = "message"
+	fieldName     = "name"
+)
+
+// The field that identifies the reference of each row in the mutable tables
+var mutableTablesKeys = map[string]string{
+	"refs":     fieldFullName,
+	"branches": fieldName,
+	"tags":     fieldName,
+}
+
+// The titles of the table rendered by a dry run
+var mutationChangesTitles = []string{
+	"repo",
+	"action",
+	"old_name",
+	"old_id",
+	"new_name",
+	"new_id",
+	"new_object_type",
+}
+
+// MutationOptions control how the changes of a mutate query are applied
+type MutationOptions struct {
+	// Report the planned changes without applying them
+	DryRun bool
+	// Identity used for annotated tags and notes commits, the repository config is used if empty
+	TaggerName  string
+	TaggerEmail string
+}
+
+// A single reference change planned by a mutate statement, old is nil
+// for created references and new is nil for removed references, objects
+// are the new objects that must be written first and the last one is
+// the object the reference points to
+type referenceChange struct {
+	repo    *git.Repository
+	old     *plumbing.Reference
+	new     *plumbing.Reference
+	objects []plumbing.EncodedObject
+}
+
+func (c *referenceChange) action() string {
+	switch {
+	case c.old == nil:
+		return "insert"
+	case c.new == nil:
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// A reference changes plan, it keeps track of the references names that
+// will be created by the changes to detect conflicts before applying them
+type mutationPlan struct {
+	changes []referenceChange
+	created map[plumbing.ReferenceName]bool
+	rows    int64
+}
+
+func newMutationPlan() *mutationPlan {
+	return &mutationPlan{
+		created: map[plumbing.ReferenceName]bool{},
+	}
+}
+
+func (p *mutationPlan) add(change referenceChange) {
+	p.addRows(change, 1)
+}
+
+// Add a reference change that mutates several rows like the notes of a notes reference
+func (p *mutationPlan) addRows(change referenceChange, rows int64) {
+	if change.new != nil {
+		p.created[change.new.Name()] = true
+	}
+
+	p.changes = append(p.changes, change)
+	p.rows += rows
+}
+
+// Check that a reference with the given name can be created in the repository
+func (p *mutationPlan) checkNewName(repo *git.Repository, name plumbing.ReferenceName) error {
+	if p.created[name] {
+		return fmt.Errorf("reference %s is changed more than once", name)
+	}
+
+	if _, err := repo.Storer.Reference(name); err == nil {
+		return fmt.Errorf("reference %s already exists", name)
+	}
+
+	return nil
+}
+
+func EvaluateMutateQuery(env *ast.Environment, repos []*git.Repository, query ast.Query, options MutationOptions) (EvaluationResult, error) {
+	var changes []referenceChange
+	var affectedRows int64
+
+	if err := evaluateSubqueries(repos, query.Subqueries); err != nil {
+		return EvaluationResult{}, err
+	}
+
+	for _, repo := range repos {
+		plan := newMutationPlan()
+		var err error
+
+		switch {
+		case query.Insert != nil:
+			err = planInsertStatement(env, query.Insert, repo, plan, options)
+		case query.Update != nil:
+			err = planUpdateStatement(env, query.Update, repo, plan, options)
+		case query.Delete != nil:
+			err = planDeleteStatement(env, query.Delete, repo, plan, options)
+		default:
+			return EvaluationResult{}, errors.New("unknown mutate query type")
+		}
+
+		if err != nil {
+			return EvaluationResult{}, err
+		}
+
+		changes = append(changes, plan.changes...)
+		affectedRows += plan.rows
+	}
+
+	if options.DryRun {
+		return EvaluationResult{
+			SelectedGroups: struct {
+				Obj ast.GitQLObject
+				Str []string
+			}{
+				Obj: referenceChangesObject(changes),
+				Str: []string{},
+			},
+			AffectedRows: affectedRows,
+		}, nil
+	}
+
+	if err := applyReferenceChanges(changes); err != nil {
+		return EvaluationResult{}, err
+	}
+
+	return EvaluationResult{AffectedRows: affectedRows}, nil
+}
+
+func planInsertStatement(
+	env *ast.Environment,
+	statement *ast.InsertStatement,
+	repo *git.Repository,
+	plan *mutationPlan,
+	options MutationOptions,
+) error {
+	if statement.TableName == notesTable {
+		return planInsertNotes(env, statement, repo, plan, options)
+	}
+
+	keyField := mutableTablesKeys[statement.TableName]
+	if !contains(statement.FieldsNames, keyField) || !contains(statement.FieldsNames, fieldCommitId) {
+		return fmt.Errorf("insert into %s requires %s and %s fields", statement.TableName, keyField, fieldCommitId)
+	}
+
+	rows, err := insertedRows(env, statement, repo)
+	if err != nil {
+		return err
+	}
+
+	for _, values := range rows {
+		name, err := mutatedReferenceName(statement.TableName, values[keyField].AsText())
+		if err != nil {
+			return err
+		}
+
+		if err := plan.checkNewName(repo, name); err != nil {
+			return err
+		}
+
+		hash, err := resolveCommitId(repo, values[fieldCommitId].AsText())
+		if err != nil {
+			return err
+		}
+
+		change := referenceChange{repo: repo, new: plumbing.NewHashReference(name, hash)}
+
+		// Tags with a message are annotated tags
+		if message, ok := values[fieldMessage]; ok && !message.DataType().IsNull() && message.AsText() != "" {
+			tagger, err := mutationSignature(repo, options)
+			if err != nil {
+				return err
+			}
+
+			tagObject, err := newTagObject(repo, name.Short(), hash, message.AsText(), tagger)
+			if err != nil {
+				return err
+			}
+
+			change.objects = []plumbing.EncodedObject{tagObject}
+			change.new = plumbing.NewHashReference(name, tagObject.Hash())
+		}
+
+		plan.add(change)
+	}
+
+	return nil
+}
+
+// Evaluate the inserted rows from the values list or the select query
+func insertedRows(
+	env *ast.Environment,
+	statement *ast.InsertStatement,
+	repo *git.Repository,
+) ([]map[string]ast.Value, error) {
+	var rows []map[string]ast.Value
+
+	if statement.Select == nil {
+		for _, row := range statement.Values {
+			values := make(map[string]ast.Value, len(row))
+			for index, expression := range row {
+				value, err := EvaluateExpression(env, expression, []string{}, nil)
+				if err != nil {
+					return nil, err
+				}
+				values[statement.FieldsNames[index]] = value
+			}
+			rows = append(rows, values)
+		}
+
+		return rows, nil
+	}
+
+	result, err := EvaluateSelectQuery(env, []*git.Repository{repo}, *statement.Select)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the selected columns to the inserted fields by position
+	titles, selectedValues := selectedRows(result)
+	if len(selectedValues) == 0 {
+		return rows, nil
+	}
+
+	if len(titles) != len(statement.FieldsNames) {
+		return nil, fmt.Errorf("expect %d selected values but got %d", len(statement.FieldsNames), len(titles))
+	}
+
+	for _, selected := range selectedValues {
+		values := make(map[string]ast.Value, len(statement.FieldsNames))
+		for index, fieldName := range statement.FieldsNames {
+			values[fieldName] = selected[index]
+		}
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+// Encode an annotated tag object without writing it to the repository
+func newTagObject(
+	repo *git.Repository,
+	name string,
+	target plumbing.Hash,
+	message string,
+	tagger *object.Signature,
+) (plumbing.EncodedObject, error) {
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+
+	tag := object.Tag{
+		Name:       name,
+		Tagger:     *tagger,
+		Message:    message,
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+
+	encoded := repo.Storer.NewEncodedObject()
+	if err := tag.Encode(encoded); err != nil {
+		return nil, fmt.Errorf("failed to encode tag %s: %w", name, err)
+	}
+
+	return encoded, nil
+}
+
+// Return the identity of the annotated tags and notes commits from the options or from the user section of the
+// repository config
+func mutationSignature(repo *git.Repository, options MutationOptions) (*object.Signature, error) {
+	name := options.TaggerName
+	email := options.TaggerEmail
+
+	if name == "" || email == "" {
+		if cfg, err := repo.ConfigScoped(config.GlobalScope); err == nil {
+			if name == "" {
+				name = cfg.User.Name
+			}
+			if email == "" {
+				email = cfg.User.Email
+			}
+		}
+	}
+
+	if name == "" || email == "" {
+		return nil, errors.New("annotated tags and notes require an identity, set user.name and user.email or use --tagger")
+	}
+
+	return &object.Signature{Name: name, Email: email, When: time.Now()}, nil
+}
+
+// nolint:gocyclo
+func planUpdateStatement(
+	env *ast.Environment,
+	statement *ast.UpdateStatement,
+	repo *git.Repository,
+	plan *mutationPlan,
+	options MutationOptions,
+) error {
+	if statement.TableName == notesTable {
+		return planUpdateNotes(env, statement, repo, plan, options)
+	}
+
+	gitqlObject, err := selectMutatedObjects(env, repo, statement.TableName, statement.Where)
+	if err != nil {
+		return err
+	}
+
+	keyField := mutableTablesKeys[statement.TableName]
+	keyIndex := indexOf(gitqlObject.Titles, keyField)
+
+	for _, object := range gitqlObject.Groups[0].Rows {
+		oldName, err := mutatedReferenceName(statement.TableName, object.Values[keyIndex].AsText())
+		if err != nil {
+			return err
+		}
+
+		oldRef, err := mutableReference(repo, oldName)
+		if err != nil {
+			return err
+		}
+
+		newName := oldName
+		newHash := oldRef.Hash()
+		var commitId *plumbing.Hash
+		var message ast.Value
+
+		for index, fieldName := range statement.FieldsNames {
+			value, err := EvaluateExpression(env, statement.FieldsValues[index], gitqlObject.Titles, object.Values)
+			if err != nil {
+				return err
+			}
+
+			switch fieldName {
+			case keyField:
+				newName, err = mutatedReferenceName(statement.TableName, value.AsText())
+			case fieldCommitId:
+				newHash, err = resolveCommitId(repo, value.AsText())
+				commitId = &newHash
+			case fieldMessage:
+				message = value
+			default:
+				err = fmt.Errorf("field %s can't be updated", fieldName)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if newName != oldName {
+			if err := plan.checkNewName(repo, newName); err != nil {
+				return err
+			}
+		}
+
+		if statement.TableName != "tags" {
+			plan.add(referenceChange{repo: repo, old: oldRef, new: plumbing.NewHashReference(newName, newHash)})
+			continue
+		}
+
+		change, err := updatedTagChange(repo, oldRef, newName, commitId, message, options)
+		if err != nil {
+			return err
+		}
+		plan.add(change)
+	}
+
+	return nil
+}
+
+// Re-create the tag object of an updated annotated tag with its tagger, the tags with a message are annotated tags
+func updatedTagChange(
+	repo *git.Repository,
+	oldRef *plumbing.Reference,
+	name plumbing.ReferenceName,
+	commitId *plumbing.Hash,
+	message ast.Value,
+	options MutationOptions,
+) (referenceChange, error) {
+	change := referenceChange{repo: repo, old: oldRef}
+
+	tag, target, err := peelTag(repo, oldRef)
+	if err != nil {
+		return change, err
+	}
+	if commitId != nil {
+		target = *commitId
+	}
+
+	var text string
+	if message != nil {
+		if !message.DataType().IsNull() {
+			text = message.AsText()
+		}
+	} else if tag != nil {
+		text = tag.Message
+	}
+
+	if text == "" {
+		change.new = plumbing.NewHashReference(name, target)
+		return change, nil
+	}
+
+	// Nothing changes the tag object of an annotated tag
+	if tag != nil && name == oldRef.Name() && commitId == nil && message == nil {
+		change.new = plumbing.NewHashReference(name, oldRef.Hash())
+		return change, nil
+	}
+
+	var tagger *object.Signature
+	if tag != nil {
+		tagger = &tag.Tagger
+	} else if tagger, err = mutationSignature(repo, options); err != nil {
+		return change, err
+	}
+
+	tagObject, err := newTagObject(repo, name.Short(), target, text, tagger)
+	if err != nil {
+		return change, err
+	}
+
+	change.objects = []plumbing.EncodedObject{tagObject}
+	change.new = plumbing.NewHashReference(name, tagObject.Hash())
+	return change, nil
+}
+
+func planDeleteStatement(
+	env *ast.Environment,
+	statement *ast.DeleteStatement,
+	repo *git.Repository,
+	plan *mutationPlan,
+	options MutationOptions,
+) error {
+	if statement.TableName == notesTable {
+		return planDeleteNotes(env, statement, repo, plan, options)
+	}
+
+	gitqlObject, err := selectMutatedObjects(env, repo, statement.TableName, statement.Where)
+	if err != nil {
+		return err
+	}
+
+	keyIndex := indexOf(gitqlObject.Titles, mutableTablesKeys[statement.TableName])
+
+	for _, object := range gitqlObject.Groups[0].Rows {
+		name, err := mutatedReferenceName(statement.TableName, object.Values[keyIndex].AsText())
+		if err != nil {
+			return err
+		}
+
+		ref, err := mutableReference(repo, name)
+		if err != nil {
+			return err
+		}
+
+		plan.add(referenceChange{repo: repo, old: ref})
+	}
+
+	return nil
+}
+
+// Apply all the changes or none of them, if one change fails the changes
+// that were already applied are reverted in reverse order
+func applyReferenceChanges(changes []referenceChange) error {
+	for index := range changes {
+		if err := applyReferenceChange(&changes[index]); err != nil {
+			for undo := index - 1; undo >= 0; undo-- {
+				if undoErr := revertReferenceChange(&changes[undo]); undoErr != nil {
+					return fmt.Errorf("%v, failed to roll back: %v", err, undoErr)
+				}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyReferenceChange(change *referenceChange) error {
+	storer := change.repo.Storer
+
+	// Objects are never removed on roll back, unreachable objects are pruned by git gc
+	for _, encoded := range change.objects {
+		if _, err := storer.SetEncodedObject(encoded); err != nil {
+			return fmt.Errorf("failed to write object %s: %w", encoded.Hash(), err)
+		}
+	}
+
+	if change.new != nil {
+		// The packed references are checked by reading them, CheckAndSetReference only reads the loose references
+		if change.old != nil && change.old.Name() == change.new.Name() {
+			current, err := storer.Reference(change.old.Name())
+			if err != nil || current.Hash() != change.old.Hash() {
+				return fmt.Errorf("reference %s was changed concurrently", change.old.Name())
+			}
+
+			if err := storer.SetReference(change.new); err != nil {
+				return fmt.Errorf("failed to update reference %s: %w", change.new.Name(), err)
+			}
+			return nil
+		}
+
+		if _, err := storer.Reference(change.new.Name()); err == nil {
+			return fmt.Errorf("reference %s already exists", change.new.Name())
+		}
+
+		if err := storer.SetReference(change.new); err != nil {
+			return fmt.Errorf("failed to create reference %s: %w", change.new.Name(), err)
+		}
+	}
+
+	if change.old != nil {
+		current, err := storer.Reference(change.old.Name())
+		if err != nil || current.Hash() != change.old.Hash() {
+			if change.new != nil {
+				_ = storer.RemoveReference(change.new.Name())
+			}
+			return fmt.Errorf("reference %s was changed concurrently", change.old.Name())
+		}
+
+		if err := storer.RemoveReference(change.old.Name()); err != nil {
+			return fmt.Errorf("failed to remove reference %s: %w", change.old.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func revertReferenceChange(change *referenceChange) error {
+	storer := change.repo.Storer
+
+	if change.new != nil && (change.old == nil || change.old.Name() != change.new.Name()) {
+		if err := storer.RemoveReference(change.new.Name()); err != nil {
+			return err
+		}
+	}
+
+	if change.old != nil {
+		if err := storer.SetReference(change.old); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func referenceChangesObject(changes []referenceChange) ast.GitQLObject {
+	rows := make([]ast.Row, 0, len(changes))
+
+	for index := range changes {
+		change := &changes[index]
+
+		repoPath := ""
+		if storer, ok := change.repo.Storer.(*filesystem.Storage); ok {
+			repoPath = storer.Filesystem().Root()
+		}
+
+		values := []ast.Value{
+			ast.TextValue{Value: repoPath},
+			ast.TextValue{Value: change.action()},
+		}
+
+		for _, ref := range []*plumbing.Reference{change.old, change.new} {
+			if ref == nil {
+				values = append(values, ast.NullValue{}, ast.NullValue{})
+				continue
+			}
+			values = append(values, ast.TextValue{Value: ref.Name().String()}, ast.TextValue{Value: ref.Hash().String()})
+		}
+
+		if len(change.objects) > 0 {
+			values = append(values, ast.TextValue{Value: change.objects[len(change.objects)-1].Type().String()})
+		} else {
+			values = append(values, ast.NullValue{})
+		}
+
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return ast.GitQLObject{
+		Titles: append([]string{}, mutationChangesTitles...),
+		Groups: []ast.Group{{Rows: rows}},
+	}
+}
+
+func selectMutatedObjects(
+	env *ast.Environment,
+	repo *git.Repository,
+	tableName string,
+	where *ast.WhereStatement,
+) (*ast.GitQLObject, error) {
+	titles := append([]string{}, ast.TablesFieldsNames[tableName]...)
+
+	group, err := SelectGQLObjects(env, repo, tableName, titles, titles, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Symbolic references are never mutated so they are not rows of the mutated tables
+	if key, ok := mutableTablesKeys[tableName]; ok {
+		keyIndex := indexOf(titles, key)
+		rows := group.Rows[:0]
+		for _, row := range group.Rows {
+			if !isSymbolicReference(repo, tableName, row.Values[keyIndex].AsText()) {
+				rows = append(rows, row)
+			}
+		}
+		group.Rows = rows
+	}
+
+	gitqlObject := &ast.GitQLObject{
+		Titles: titles,
+		Groups: []ast.Group{*group},
+	}
+
+	if where != nil {
+		if err := executeWhereStatement(env, where, gitqlObject); err != nil {
+			return nil, err
+		}
+	}
+
+	return gitqlObject, nil
+}
+
+func isSymbolicReference(repo *git.Repository, tableName, name string) bool {
+	referenceName, err := mutatedReferenceName(tableName, name)
+	if err != nil {
+		referenceName = plumbing.ReferenceName(name)
+	}
+
+	ref, err := repo.Storer.Reference(referenceName)
+	return err == nil && ref.Type() == plumbing.SymbolicReference
+}
+
+func mutatedReferenceName(tableName, name string) (plumbing.ReferenceName, error) {
+	var referenceName plumbing.ReferenceName
+
+	switch tableName {
+	case "refs":
+		if !strings.HasPrefix(name, "refs/") {
+			return "", fmt.Errorf("reference name %s must start with refs/", name)
+		}
+		referenceName = plumbing.ReferenceName(name)
+	case "branches":
+		// Branches are listed with their full names, short names are local branches
+		if strings.HasPrefix(name, "refs/heads/") || strings.HasPrefix(name, "refs/remotes/") {
+			referenceName = plumbing.ReferenceName(name)
+		} else {
+			referenceName = plumbing.NewBranchReferenceName(name)
+		}
+	case "tags":
+		referenceName = plumbing.NewTagReferenceName(name)
+	default:
+		return "", fmt.Errorf("table %s can't be mutated", tableName)
+	}
+
+	if err := referenceName.Validate(); err != nil {
+		return "", fmt.Errorf("invalid reference name %s: %w", name, err)
+	}
+
+	return referenceName, nil
+}
+
+// Return the reference with the given name if it exists and can be changed,
+// symbolic references and the checked out branch are never changed
+func mutableReference(repo *git.Repository, name plumbing.ReferenceName) (*plumbing.Reference, error) {
+	ref, err := repo.Storer.Reference(name)
+	if err != nil {
+		return nil, fmt.Errorf("reference %s not found", name)
+	}
+
+	if ref.Type() != plumbing.HashReference {
+		return nil, fmt.Errorf("symbolic reference %s can't be mutated", name)
+	}
+
+	if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil {
+		if head.Type() == plumbing.SymbolicReference && head.Target() == name {
+			return nil, fmt.Errorf("checked out branch %s can't be mutated", name)
+		}
+	}
+
+	return ref, nil
+}
+
+func resolveCommitId(repo *git.Repository, revision string) (plumbing.Hash, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unknown revision %s", revision)
+	}
+
+	return *hash, nil
+}
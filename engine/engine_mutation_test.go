@@ -0,0 +1,616 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+)
+
+const (
+	mutationFile = "ggql-engine-mutation-test.txt"
+	mutationRepo = "ggql-engine-mutation-test.git"
+)
+
+func newMutationRepo() *git.Repository {
+	// Create a new repository
+	_, _ = git.PlainInit(mutationRepo, false)
+	repo, _ := git.PlainOpen(mutationRepo)
+
+	tree, _ := repo.Worktree()
+
+	// Create a new file
+	filePath := filepath.Join(tree.Filesystem.Root(), mutationFile)
+	file, _ := os.Create(filePath)
+	_, _ = file.WriteString("hello world")
+	_ = file.Close()
+
+	// Create a new commit
+	_, _ = tree.Add(mutationFile)
+	commit, _ := tree.Commit("Adding "+mutationFile, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  "name",
+			Email: "name@example.com",
+			When:  time.Now(),
+		},
+	})
+
+	// Create a new branch
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", commit))
+
+	return repo
+}
+
+func deleteMutationRepo() {
+	_ = os.RemoveAll(mutationRepo)
+}
+
+func newMutationEnv() ast.Environment {
+	return ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+}
+
+func fullNameEquals(name string) *ast.WhereStatement {
+	return &ast.WhereStatement{
+		Condition: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: fieldFullName},
+			Operator: ast.COEqual,
+			Right: &ast.StringExpression{
+				Value:     name,
+				ValueType: ast.StringValueText,
+			},
+		},
+	}
+}
+
+func TestEvaluateMutateQuery(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	query := ast.Query{
+		Delete: &ast.DeleteStatement{
+			TableName: "refs",
+			Where:     fullNameEquals("refs/heads/feature"),
+		},
+	}
+
+	// Dry run reports the changes without applying them
+	ret, err := EvaluateMutateQuery(&env, []*git.Repository{repo}, query, MutationOptions{DryRun: true})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), ret.AffectedRows)
+	assert.Equal(t, mutationChangesTitles, ret.SelectedGroups.Obj.Titles)
+	assert.Equal(t, "delete", ret.SelectedGroups.Obj.Groups[0].Rows[0].Values[1].AsText())
+
+	_, err = repo.Storer.Reference("refs/heads/feature")
+	assert.Equal(t, nil, err)
+
+	ret, err = EvaluateMutateQuery(&env, []*git.Repository{repo}, query, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(1), ret.AffectedRows)
+
+	_, err = repo.Storer.Reference("refs/heads/feature")
+	assert.NotEqual(t, nil, err)
+
+	_, err = EvaluateMutateQuery(&env, []*git.Repository{repo}, ast.Query{}, MutationOptions{})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPlanInsertStatement(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	statement := ast.InsertStatement{
+		TableName:   "refs",
+		FieldsNames: []string{fieldFullName, fieldCommitId},
+		Values: [][]ast.Expression{
+			{
+				&ast.StringExpression{Value: "refs/tags/v1", ValueType: ast.StringValueText},
+				&ast.StringExpression{Value: "HEAD", ValueType: ast.StringValueText},
+			},
+		},
+	}
+
+	plan := newMutationPlan()
+	err := planInsertStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, "insert", plan.changes[0].action())
+
+	// Inserting the same reference twice fails
+	err = planInsertStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.NotEqual(t, nil, err)
+
+	// Inserting an existing reference fails
+	statement.Values[0][0] = &ast.StringExpression{Value: "refs/heads/feature", ValueType: ast.StringValueText}
+	err = planInsertStatement(&env, &statement, repo, newMutationPlan(), MutationOptions{})
+	assert.NotEqual(t, nil, err)
+
+	// Inserting without a commit id fails
+	statement.FieldsNames = []string{fieldFullName}
+	statement.Values = [][]ast.Expression{{&ast.StringExpression{Value: "refs/tags/v2", ValueType: ast.StringValueText}}}
+	err = planInsertStatement(&env, &statement, repo, newMutationPlan(), MutationOptions{})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPlanInsertStatementAnnotatedTag(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	statement := ast.InsertStatement{
+		TableName:   "tags",
+		FieldsNames: []string{fieldName, fieldCommitId, fieldMessage},
+		Values: [][]ast.Expression{
+			{
+				&ast.StringExpression{Value: "v1", ValueType: ast.StringValueText},
+				&ast.StringExpression{Value: "HEAD", ValueType: ast.StringValueText},
+				&ast.StringExpression{Value: "Release v1", ValueType: ast.StringValueText},
+			},
+		},
+	}
+
+	options := MutationOptions{TaggerName: "tagger", TaggerEmail: "tagger@example.com"}
+
+	plan := newMutationPlan()
+	err := planInsertStatement(&env, &statement, repo, plan, options)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, plumbing.TagObject, plan.changes[0].objects[0].Type())
+	assert.Equal(t, plan.changes[0].objects[0].Hash(), plan.changes[0].new.Hash())
+
+	// The tag object is written only when the changes are applied
+	_, err = repo.TagObject(plan.changes[0].objects[0].Hash())
+	assert.NotEqual(t, nil, err)
+
+	err = applyReferenceChanges(plan.changes)
+	assert.Equal(t, nil, err)
+
+	tag, err := repo.TagObject(plan.changes[0].objects[0].Hash())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "tagger", tag.Tagger.Name)
+	assert.Equal(t, "Release v1\n", tag.Message)
+}
+
+func TestInsertedRows(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	// Test: SELECT title, commit_id FROM commits
+	statement := ast.InsertStatement{
+		TableName:   "tags",
+		FieldsNames: []string{fieldName, fieldCommitId},
+		Select: &ast.GQLQuery{
+			Statements: map[string]ast.Statement{
+				"select": &ast.SelectStatement{
+					TableName:    "commits",
+					FieldsNames:  []string{"title", "commit_id"},
+					FieldsValues: []ast.Expression{&ast.SymbolExpression{Value: "title"}, &ast.SymbolExpression{Value: "commit_id"}},
+					AliasTable:   map[string]string{},
+				},
+			},
+			HiddenSelections: []string{},
+		},
+	}
+
+	rows, err := insertedRows(&env, &statement, repo)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Adding "+mutationFile, rows[0][fieldName].AsText())
+
+	head, _ := repo.Head()
+	assert.Equal(t, head.Hash().String(), rows[0][fieldCommitId].AsText())
+}
+
+func TestNewTagObject(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+	tagger := object.Signature{Name: "tagger", Email: "tagger@example.com", When: time.Now()}
+
+	encoded, err := newTagObject(repo, "v1", head.Hash(), "message", &tagger)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plumbing.TagObject, encoded.Type())
+
+	tag, err := object.DecodeTag(repo.Storer, encoded)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "v1", tag.Name)
+	assert.Equal(t, head.Hash(), tag.Target)
+	assert.Equal(t, "message\n", tag.Message)
+}
+
+func TestMutationSignature(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	signature, err := mutationSignature(repo, MutationOptions{TaggerName: "tagger", TaggerEmail: "tagger@example.com"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "tagger", signature.Name)
+	assert.Equal(t, "tagger@example.com", signature.Email)
+
+	cfg, _ := repo.Config()
+	cfg.User.Name = "user"
+	cfg.User.Email = "user@example.com"
+	_ = repo.SetConfig(cfg)
+
+	signature, err = mutationSignature(repo, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "user", signature.Name)
+	assert.Equal(t, "user@example.com", signature.Email)
+}
+
+func TestPlanUpdateStatement(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	statement := ast.UpdateStatement{
+		TableName:   "refs",
+		FieldsNames: []string{fieldFullName},
+		FieldsValues: []ast.Expression{
+			&ast.StringExpression{Value: "refs/heads/renamed", ValueType: ast.StringValueText},
+		},
+		Where: fullNameEquals("refs/heads/feature"),
+	}
+
+	plan := newMutationPlan()
+	err := planUpdateStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, "update", plan.changes[0].action())
+	assert.Equal(t, plumbing.ReferenceName("refs/heads/renamed"), plan.changes[0].new.Name())
+
+	// Updating to an unknown revision fails
+	statement.FieldsNames = []string{fieldCommitId}
+	statement.FieldsValues = []ast.Expression{&ast.StringExpression{Value: "unknown", ValueType: ast.StringValueText}}
+	err = planUpdateStatement(&env, &statement, repo, newMutationPlan(), MutationOptions{})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestPlanUpdateTags(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+	headCommit, _ := repo.CommitObject(head.Hash())
+	child := storeObject(repo, &object.Commit{
+		Author:       headCommit.Author,
+		Committer:    headCommit.Committer,
+		Message:      "Child",
+		TreeHash:     headCommit.TreeHash,
+		ParentHashes: []plumbing.Hash{head.Hash()},
+	})
+
+	tagger := object.Signature{Name: "tagger", Email: "tagger@example.com", When: time.Unix(1700000000, 0)}
+	_, err := repo.CreateTag("v2", head.Hash(), &git.CreateTagOptions{Tagger: &tagger, Message: "Release"})
+	assert.Equal(t, nil, err)
+	_, err = repo.CreateTag("v3", head.Hash(), nil)
+	assert.Equal(t, nil, err)
+
+	updateTag := func(name, fieldName string, value ast.Expression) referenceChange {
+		statement := ast.UpdateStatement{
+			TableName:    "tags",
+			FieldsNames:  []string{fieldName},
+			FieldsValues: []ast.Expression{value},
+			Where: &ast.WhereStatement{
+				Condition: &ast.ComparisonExpression{
+					Left:     &ast.SymbolExpression{Value: "name"},
+					Operator: ast.COEqual,
+					Right:    &ast.StringExpression{Value: name, ValueType: ast.StringValueText},
+				},
+			},
+		}
+
+		plan := newMutationPlan()
+		err := planUpdateStatement(&env, &statement, repo, plan, notesOptions)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, 1, len(plan.changes))
+		return plan.changes[0]
+	}
+
+	decodeTag := func(change referenceChange) *object.Tag {
+		assert.Equal(t, 1, len(change.objects))
+		assert.Equal(t, change.objects[0].Hash(), change.new.Hash())
+		tag, err := object.DecodeTag(repo.Storer, change.objects[0])
+		assert.Equal(t, nil, err)
+		return tag
+	}
+
+	text := func(value string) ast.Expression {
+		return &ast.StringExpression{Value: value, ValueType: ast.StringValueText}
+	}
+
+	// The annotated tag is unchanged by its own name
+	change := updateTag("v2", fieldName, text("v2"))
+	assert.Equal(t, 0, len(change.objects))
+	assert.Equal(t, change.old.Hash(), change.new.Hash())
+
+	// The annotated tag keeps its tagger and message when it moves to another commit
+	tag := decodeTag(updateTag("v2", fieldCommitId, text(child.String())))
+	assert.Equal(t, "v2", tag.Name)
+	assert.Equal(t, child, tag.Target)
+	assert.Equal(t, tagger.Email, tag.Tagger.Email)
+	assert.Equal(t, "Release\n", tag.Message)
+
+	// The annotated tag keeps its tagger and commit with a new message
+	tag = decodeTag(updateTag("v2", fieldMessage, text("Second release")))
+	assert.Equal(t, head.Hash(), tag.Target)
+	assert.Equal(t, tagger.Email, tag.Tagger.Email)
+	assert.Equal(t, "Second release\n", tag.Message)
+
+	// The annotated tag is renamed with its tag object
+	tag = decodeTag(updateTag("v2", fieldName, text("v2.0")))
+	assert.Equal(t, "v2.0", tag.Name)
+
+	// The lightweight tag with a message is an annotated tag of the mutation identity
+	tag = decodeTag(updateTag("v3", fieldMessage, text("Annotated")))
+	assert.Equal(t, head.Hash(), tag.Target)
+	assert.Equal(t, "name@example.com", tag.Tagger.Email)
+
+	// The annotated tag without a message is a lightweight tag
+	change = updateTag("v2", fieldMessage, text(""))
+	assert.Equal(t, 0, len(change.objects))
+	assert.Equal(t, head.Hash(), change.new.Hash())
+}
+
+func TestPlanDeleteStatement(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	statement := ast.DeleteStatement{
+		TableName: "refs",
+		Where:     fullNameEquals("refs/heads/feature"),
+	}
+
+	plan := newMutationPlan()
+	err := planDeleteStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, "delete", plan.changes[0].action())
+
+	// The checked out branch is never deleted
+	statement.Where = fullNameEquals("refs/heads/master")
+	err = planDeleteStatement(&env, &statement, repo, newMutationPlan(), MutationOptions{})
+	assert.NotEqual(t, nil, err)
+
+	// The remote branches and the symbolic origin/HEAD which is never a row to mutate
+	head, _ := repo.Head()
+	_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/master", head.Hash()))
+	_ = repo.Storer.SetReference(plumbing.NewSymbolicReference("refs/remotes/origin/HEAD", "refs/remotes/origin/master"))
+
+	// Test: DELETE FROM branches WHERE is_remote = false AND name != "refs/heads/master"
+	statement = ast.DeleteStatement{
+		TableName: "branches",
+		Where: &ast.WhereStatement{
+			Condition: &ast.LogicalExpression{
+				Left: &ast.ComparisonExpression{
+					Left:     &ast.SymbolExpression{Value: "is_remote"},
+					Operator: ast.COEqual,
+					Right:    &ast.BooleanExpression{IsTrue: false},
+				},
+				Operator: ast.LOAnd,
+				Right: &ast.ComparisonExpression{
+					Left:     &ast.SymbolExpression{Value: "name"},
+					Operator: ast.CONotEqual,
+					Right:    &ast.StringExpression{Value: "refs/heads/master", ValueType: ast.StringValueText},
+				},
+			},
+		},
+	}
+
+	plan = newMutationPlan()
+	err = planDeleteStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, plumbing.ReferenceName("refs/heads/feature"), plan.changes[0].old.Name())
+
+	// Test: DELETE FROM branches WHERE is_remote = true
+	statement.Where = &ast.WhereStatement{
+		Condition: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "is_remote"},
+			Operator: ast.COEqual,
+			Right:    &ast.BooleanExpression{IsTrue: true},
+		},
+	}
+
+	plan = newMutationPlan()
+	err = planDeleteStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, plumbing.ReferenceName("refs/remotes/origin/master"), plan.changes[0].old.Name())
+
+	// Test: DELETE FROM tags WHERE is_annotated = true
+	_, _ = repo.CreateTag("lightweight", head.Hash(), nil)
+	_, _ = repo.CreateTag("annotated", head.Hash(), &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "name", Email: "name@example.com", When: time.Now()},
+		Message: "Annotated",
+	})
+
+	statement = ast.DeleteStatement{
+		TableName: "tags",
+		Where: &ast.WhereStatement{
+			Condition: &ast.ComparisonExpression{
+				Left:     &ast.SymbolExpression{Value: "is_annotated"},
+				Operator: ast.COEqual,
+				Right:    &ast.BooleanExpression{IsTrue: true},
+			},
+		},
+	}
+
+	plan = newMutationPlan()
+	err = planDeleteStatement(&env, &statement, repo, plan, MutationOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, plumbing.ReferenceName("refs/tags/annotated"), plan.changes[0].old.Name())
+}
+
+func TestApplyReferenceChanges(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	feature, _ := repo.Storer.Reference("refs/heads/feature")
+	renamed := plumbing.NewHashReference("refs/heads/renamed", feature.Hash())
+
+	changes := []referenceChange{
+		{repo: repo, old: feature, new: renamed},
+		{repo: repo, new: plumbing.NewHashReference("refs/heads/master", feature.Hash())},
+	}
+
+	// The second change fails so the rename is rolled back
+	err := applyReferenceChanges(changes)
+	assert.NotEqual(t, nil, err)
+
+	_, err = repo.Storer.Reference("refs/heads/feature")
+	assert.Equal(t, nil, err)
+
+	_, err = repo.Storer.Reference("refs/heads/renamed")
+	assert.NotEqual(t, nil, err)
+
+	err = applyReferenceChanges(changes[:1])
+	assert.Equal(t, nil, err)
+
+	_, err = repo.Storer.Reference("refs/heads/renamed")
+	assert.Equal(t, nil, err)
+}
+
+func TestApplyReferenceChangePacked(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+	feature, _ := repo.Storer.Reference("refs/heads/feature")
+
+	// A cloned repository has its references in the packed-refs file
+	storer, _ := repo.Storer.(*filesystem.Storage)
+	err := storer.PackRefs()
+	assert.Equal(t, nil, err)
+
+	updated := plumbing.NewHashReference("refs/heads/feature", plumbing.NewHash("0123456789012345678901234567890123456789"))
+	err = applyReferenceChange(&referenceChange{repo: repo, old: feature, new: updated})
+	assert.Equal(t, nil, err)
+
+	ref, err := repo.Storer.Reference("refs/heads/feature")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, updated.Hash(), ref.Hash())
+
+	// The reference changed since the plan is not updated
+	err = applyReferenceChange(&referenceChange{repo: repo, old: feature, new: plumbing.NewHashReference("refs/heads/feature", head.Hash())})
+	assert.NotEqual(t, nil, err)
+}
+
+func TestReferenceChangesObject(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	feature, _ := repo.Storer.Reference("refs/heads/feature")
+
+	gitqlObject := referenceChangesObject([]referenceChange{{repo: repo, old: feature}})
+	assert.Equal(t, mutationChangesTitles, gitqlObject.Titles)
+	assert.Equal(t, 1, len(gitqlObject.Groups[0].Rows))
+
+	values := gitqlObject.Groups[0].Rows[0].Values
+	assert.Equal(t, "refs/heads/feature", values[2].AsText())
+	assert.Equal(t, feature.Hash().String(), values[3].AsText())
+	assert.Equal(t, true, values[4].DataType().IsNull())
+	assert.Equal(t, true, values[6].DataType().IsNull())
+}
+
+func TestSelectMutatedObjects(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	gitqlObject, err := selectMutatedObjects(&env, repo, "refs", fullNameEquals("refs/heads/feature"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(gitqlObject.Groups[0].Rows))
+
+	// The NULL tagger of a lightweight tag never matches a tagger
+	head, _ := repo.Head()
+	_, _ = repo.CreateTag("lightweight", head.Hash(), nil)
+
+	taggerEquals := &ast.WhereStatement{
+		Condition: &ast.ComparisonExpression{
+			Left:     &ast.SymbolExpression{Value: "tagger_email"},
+			Operator: ast.COEqual,
+			Right:    &ast.StringExpression{Value: "nobody@example.com", ValueType: ast.StringValueText},
+		},
+	}
+
+	gitqlObject, err = selectMutatedObjects(&env, repo, "tags", taggerEquals)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(gitqlObject.Groups[0].Rows))
+}
+
+func TestMutatedReferenceName(t *testing.T) {
+	name, err := mutatedReferenceName("refs", "refs/heads/main")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plumbing.ReferenceName("refs/heads/main"), name)
+
+	_, err = mutatedReferenceName("refs", "main")
+	assert.NotEqual(t, nil, err)
+
+	name, err = mutatedReferenceName("branches", "main")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plumbing.ReferenceName("refs/heads/main"), name)
+
+	name, err = mutatedReferenceName("branches", "refs/remotes/origin/main")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plumbing.ReferenceName("refs/remotes/origin/main"), name)
+
+	name, err = mutatedReferenceName("tags", "v1")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, plumbing.ReferenceName("refs/tags/v1"), name)
+
+	_, err = mutatedReferenceName("commits", "refs/heads/main")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestMutableReference(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	_, err := mutableReference(repo, "refs/heads/feature")
+	assert.Equal(t, nil, err)
+
+	_, err = mutableReference(repo, "refs/heads/master")
+	assert.NotEqual(t, nil, err)
+
+	_, err = mutableReference(repo, "refs/heads/unknown")
+	assert.NotEqual(t, nil, err)
+}
+
+func TestResolveCommitId(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	hash, err := resolveCommitId(repo, "HEAD")
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, plumbing.ZeroHash, hash)
+
+	_, err = resolveCommitId(repo, "unknown")
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,449 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/ggql/ggql/ast"
+)
+
+const (
+	fieldNote        = "note"
+	fieldNotesRef    = "notes_ref"
+	notesTable       = "notes"
+	defaultNotesRef  = "refs/notes/commits"
+	notesRefPrefix   = "refs/notes/"
+	notesMessage     = "Notes added by 'ggql'\n"
+	notesFanoutWidth = 2
+)
+
+// The notes of a notes reference, the notes are blobs named by the annotated object id and the other files of the
+// notes tree are kept as they are
+type notesTree struct {
+	ref    *plumbing.Reference
+	commit *object.Commit
+	notes  map[plumbing.Hash]plumbing.Hash
+	others []object.TreeEntry
+}
+
+// A notes changes plan, all the notes changed in a notes reference are written by a single notes commit like
+// `git notes`, a nil note removes the note of the object
+type notesPlan struct {
+	repo  *git.Repository
+	trees map[plumbing.ReferenceName]*notesTree
+	edits map[plumbing.ReferenceName]map[plumbing.Hash]*string
+	rows  map[plumbing.ReferenceName]int64
+}
+
+func newNotesPlan(repo *git.Repository) *notesPlan {
+	return &notesPlan{
+		repo:  repo,
+		trees: map[plumbing.ReferenceName]*notesTree{},
+		edits: map[plumbing.ReferenceName]map[plumbing.Hash]*string{},
+		rows:  map[plumbing.ReferenceName]int64{},
+	}
+}
+
+// Return the notes of the notes reference before the changes, the tree is empty if the reference doesn't exist
+func (p *notesPlan) tree(name plumbing.ReferenceName) (*notesTree, error) {
+	if tree, ok := p.trees[name]; ok {
+		return tree, nil
+	}
+
+	tree := &notesTree{notes: map[plumbing.Hash]plumbing.Hash{}}
+	if _, err := p.repo.Storer.Reference(name); err == nil {
+		ref, err := mutableReference(p.repo, name)
+		if err != nil {
+			return nil, err
+		}
+
+		commit, err := p.repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("notes reference %s doesn't point to a commit", name)
+		}
+
+		root, err := commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+
+		tree.ref = ref
+		tree.commit = commit
+		tree.others, err = readNotesTree(root, "", tree.notes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.trees[name] = tree
+	return tree, nil
+}
+
+// Return true if the object has a note in the notes reference after the planned changes
+func (p *notesPlan) hasNote(name plumbing.ReferenceName, id plumbing.Hash) (bool, error) {
+	if note, ok := p.edits[name][id]; ok {
+		return note != nil, nil
+	}
+
+	tree, err := p.tree(name)
+	if err != nil {
+		return false, err
+	}
+
+	_, ok := tree.notes[id]
+	return ok, nil
+}
+
+func (p *notesPlan) set(name plumbing.ReferenceName, id plumbing.Hash, note *string) error {
+	if _, ok := p.edits[name][id]; ok {
+		return fmt.Errorf("note of %s in %s is changed more than once", id, name)
+	}
+
+	if _, ok := p.edits[name]; !ok {
+		p.edits[name] = map[plumbing.Hash]*string{}
+	}
+
+	p.edits[name][id] = note
+	return nil
+}
+
+// Add the notes commit of each changed notes reference to the mutation plan
+func (p *notesPlan) addChanges(plan *mutationPlan, options MutationOptions) error {
+	names := make([]plumbing.ReferenceName, 0, len(p.edits))
+	for name := range p.edits {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	for _, name := range names {
+		change, err := p.change(name, options)
+		if err != nil {
+			return err
+		}
+
+		plan.addRows(change, p.rows[name])
+	}
+
+	return nil
+}
+
+// Encode the notes blobs, tree and commit of the notes reference without writing them to the repository, the notes
+// tree is written without fanout directories which git adds back when it writes the notes
+func (p *notesPlan) change(name plumbing.ReferenceName, options MutationOptions) (referenceChange, error) {
+	tree, err := p.tree(name)
+	if err != nil {
+		return referenceChange{}, err
+	}
+
+	change := referenceChange{repo: p.repo, old: tree.ref}
+	entries := append([]object.TreeEntry{}, tree.others...)
+
+	for id, blob := range tree.notes {
+		if _, ok := p.edits[name][id]; !ok {
+			entries = append(entries, object.TreeEntry{Name: id.String(), Mode: filemode.Regular, Hash: blob})
+		}
+	}
+
+	for id, note := range p.edits[name] {
+		if note == nil {
+			continue
+		}
+
+		encoded, err := newBlobObject(p.repo, *note)
+		if err != nil {
+			return referenceChange{}, err
+		}
+
+		change.objects = append(change.objects, encoded)
+		entries = append(entries, object.TreeEntry{Name: id.String(), Mode: filemode.Regular, Hash: encoded.Hash()})
+	}
+
+	// Tree entries are sorted by name and directories are compared with a trailing slash
+	sort.Slice(entries, func(i, j int) bool {
+		return treeEntrySortName(entries[i]) < treeEntrySortName(entries[j])
+	})
+
+	encodedTree := p.repo.Storer.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(encodedTree); err != nil {
+		return referenceChange{}, fmt.Errorf("failed to encode notes tree of %s: %w", name, err)
+	}
+	change.objects = append(change.objects, encodedTree)
+
+	signature, err := mutationSignature(p.repo, options)
+	if err != nil {
+		return referenceChange{}, err
+	}
+
+	commit := object.Commit{
+		Author:    *signature,
+		Committer: *signature,
+		Message:   notesMessage,
+		TreeHash:  encodedTree.Hash(),
+	}
+	if tree.commit != nil {
+		commit.ParentHashes = []plumbing.Hash{tree.commit.Hash}
+	}
+
+	encodedCommit := p.repo.Storer.NewEncodedObject()
+	if err := commit.Encode(encodedCommit); err != nil {
+		return referenceChange{}, fmt.Errorf("failed to encode notes commit of %s: %w", name, err)
+	}
+	change.objects = append(change.objects, encodedCommit)
+
+	change.new = plumbing.NewHashReference(name, encodedCommit.Hash())
+	return change, nil
+}
+
+// Collect the notes of the tree and its fanout directories named by the first characters of the object ids, and
+// return the other entries of the tree
+func readNotesTree(tree *object.Tree, prefix string, notes map[plumbing.Hash]plumbing.Hash) ([]object.TreeEntry, error) {
+	var others []object.TreeEntry
+
+	for _, entry := range tree.Entries {
+		name := prefix + entry.Name
+
+		if entry.Mode.IsFile() && plumbing.IsHash(name) {
+			notes[plumbing.NewHash(name)] = entry.Hash
+			continue
+		}
+
+		if entry.Mode == filemode.Dir && len(entry.Name) == notesFanoutWidth && isHexadecimal(entry.Name) {
+			subtree, err := tree.Tree(entry.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, err := readNotesTree(subtree, name, notes); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if prefix == "" {
+			others = append(others, entry)
+		}
+	}
+
+	return others, nil
+}
+
+func isHexadecimal(text string) bool {
+	return strings.Trim(strings.ToLower(text), "0123456789abcdef") == ""
+}
+
+func treeEntrySortName(entry object.TreeEntry) string {
+	if entry.Mode == filemode.Dir {
+		return entry.Name + "/"
+	}
+	return entry.Name
+}
+
+// Encode a blob object without writing it to the repository
+func newBlobObject(repo *git.Repository, content string) (plumbing.EncodedObject, error) {
+	encoded := repo.Storer.NewEncodedObject()
+	encoded.SetType(plumbing.BlobObject)
+
+	writer, err := encoded.Writer()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.WriteString(writer, content); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return encoded, nil
+}
+
+// Return the notes reference name, short names are in the notes namespace like `git notes --ref`
+func notesReferenceName(name string) (plumbing.ReferenceName, error) {
+	if name == "" {
+		return defaultNotesRef, nil
+	}
+
+	if !strings.HasPrefix(name, "refs/") {
+		name = notesRefPrefix + name
+	}
+
+	if !strings.HasPrefix(name, notesRefPrefix) {
+		return "", fmt.Errorf("notes reference name %s must start with %s", name, notesRefPrefix)
+	}
+
+	referenceName := plumbing.ReferenceName(name)
+	if err := referenceName.Validate(); err != nil {
+		return "", fmt.Errorf("invalid reference name %s: %w", name, err)
+	}
+
+	return referenceName, nil
+}
+
+// Return the note with a trailing newline like `git notes add`
+func noteContent(value ast.Value) string {
+	note := value.AsText()
+	if !strings.HasSuffix(note, "\n") {
+		note += "\n"
+	}
+	return note
+}
+
+func planInsertNotes(
+	env *ast.Environment,
+	statement *ast.InsertStatement,
+	repo *git.Repository,
+	plan *mutationPlan,
+	options MutationOptions,
+) error {
+	if !contains(statement.FieldsNames, fieldCommitId) || !contains(statement.FieldsNames, fieldNote) {
+		return fmt.Errorf("insert into %s requires %s and %s fields", notesTable, fieldCommitId, fieldNote)
+	}
+
+	rows, err := insertedRows(env, statement, repo)
+	if err != nil {
+		return err
+	}
+
+	notes := newNotesPlan(repo)
+	for _, values := range rows {
+		var notesRef string
+		if value, ok := values[fieldNotesRef]; ok && !value.DataType().IsNull() {
+			notesRef = value.AsText()
+		}
+
+		name, err := notesReferenceName(notesRef)
+		if err != nil {
+			return err
+		}
+
+		id, err := resolveCommitId(repo, values[fieldCommitId].AsText())
+		if err != nil {
+			return err
+		}
+
+		exists, err := notes.hasNote(name, id)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("note of %s already exists in %s", id, name)
+		}
+
+		note := noteContent(values[fieldNote])
+		if err := notes.set(name, id, &note); err != nil {
+			return err
+		}
+		notes.rows[name]++
+	}
+
+	return notes.addChanges(plan, options)
+}
+
+func planUpdateNotes(
+	env *ast.Environment,
+	statement *ast.UpdateStatement,
+	repo *git.Repository,
+	plan *mutationPlan,
+	options MutationOptions,
+) error {
+	gitqlObject, err := selectMutatedObjects(env, repo, notesTable, statement.Where)
+	if err != nil {
+		return err
+	}
+
+	notesRefIndex := indexOf(gitqlObject.Titles, fieldNotesRef)
+	commitIdIndex := indexOf(gitqlObject.Titles, fieldCommitId)
+	noteIndex := indexOf(gitqlObject.Titles, fieldNote)
+
+	notes := newNotesPlan(repo)
+	for _, object := range gitqlObject.Groups[0].Rows {
+		oldName := plumbing.ReferenceName(object.Values[notesRefIndex].AsText())
+		oldId := plumbing.NewHash(object.Values[commitIdIndex].AsText())
+
+		newName := oldName
+		newId := oldId
+		note := object.Values[noteIndex].AsText()
+
+		for index, fieldName := range statement.FieldsNames {
+			value, err := EvaluateExpression(env, statement.FieldsValues[index], gitqlObject.Titles, object.Values)
+			if err != nil {
+				return err
+			}
+
+			switch fieldName {
+			case fieldNotesRef:
+				newName, err = notesReferenceName(value.AsText())
+			case fieldCommitId:
+				newId, err = resolveCommitId(repo, value.AsText())
+			case fieldNote:
+				note = noteContent(value)
+			default:
+				err = fmt.Errorf("field %s can't be updated", fieldName)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// A note moved to another object or notes reference is removed from the old one
+		if newName != oldName || newId != oldId {
+			exists, err := notes.hasNote(newName, newId)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("note of %s already exists in %s", newId, newName)
+			}
+
+			if err := notes.set(oldName, oldId, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := notes.set(newName, newId, &note); err != nil {
+			return err
+		}
+		notes.rows[newName]++
+	}
+
+	return notes.addChanges(plan, options)
+}
+
+func planDeleteNotes(
+	env *ast.Environment,
+	statement *ast.DeleteStatement,
+	repo *git.Repository,
+	plan *mutationPlan,
+	options MutationOptions,
+) error {
+	gitqlObject, err := selectMutatedObjects(env, repo, notesTable, statement.Where)
+	if err != nil {
+		return err
+	}
+
+	notesRefIndex := indexOf(gitqlObject.Titles, fieldNotesRef)
+	commitIdIndex := indexOf(gitqlObject.Titles, fieldCommitId)
+
+	notes := newNotesPlan(repo)
+	for _, object := range gitqlObject.Groups[0].Rows {
+		name := plumbing.ReferenceName(object.Values[notesRefIndex].AsText())
+		if err := notes.set(name, plumbing.NewHash(object.Values[commitIdIndex].AsText()), nil); err != nil {
+			return err
+		}
+		notes.rows[name]++
+	}
+
+	return notes.addChanges(plan, options)
+}
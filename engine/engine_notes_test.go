@@ -0,0 +1,253 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+)
+
+var notesOptions = MutationOptions{TaggerName: "name", TaggerEmail: "name@example.com"}
+
+func notesInsertStatement(commitId, note string) *ast.InsertStatement {
+	return &ast.InsertStatement{
+		TableName:   notesTable,
+		FieldsNames: []string{fieldCommitId, fieldNote},
+		Values: [][]ast.Expression{
+			{
+				&ast.StringExpression{Value: commitId, ValueType: ast.StringValueText},
+				&ast.StringExpression{Value: note, ValueType: ast.StringValueText},
+			},
+		},
+	}
+}
+
+func readNotes(t *testing.T, repo *git.Repository, name plumbing.ReferenceName) map[plumbing.Hash]string {
+	ref, err := repo.Storer.Reference(name)
+	assert.Nil(t, err)
+
+	commit, err := repo.CommitObject(ref.Hash())
+	assert.Nil(t, err)
+
+	tree, err := commit.Tree()
+	assert.Nil(t, err)
+
+	blobs := map[plumbing.Hash]plumbing.Hash{}
+	_, err = readNotesTree(tree, "", blobs)
+	assert.Nil(t, err)
+
+	notes := map[plumbing.Hash]string{}
+	for id, hash := range blobs {
+		blob, err := repo.BlobObject(hash)
+		assert.Nil(t, err)
+		file := object.NewFile(id.String(), filemode.Regular, blob)
+		notes[id], _ = file.Contents()
+	}
+	return notes
+}
+
+func TestPlanInsertNotes(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+
+	plan := newMutationPlan()
+	err := planInsertStatement(&env, notesInsertStatement("HEAD", "Build: passed"), repo, plan, notesOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, int64(1), plan.rows)
+	assert.Equal(t, "insert", plan.changes[0].action())
+	assert.Equal(t, plumbing.ReferenceName(defaultNotesRef), plan.changes[0].new.Name())
+
+	err = applyReferenceChanges(plan.changes)
+	assert.Nil(t, err)
+	assert.Equal(t, map[plumbing.Hash]string{head.Hash(): "Build: passed\n"}, readNotes(t, repo, defaultNotesRef))
+
+	// Inserting an existing note fails
+	err = planInsertStatement(&env, notesInsertStatement("HEAD", "Build: failed"), repo, newMutationPlan(), notesOptions)
+	assert.NotNil(t, err)
+
+	// Inserting without a note fails
+	statement := notesInsertStatement("HEAD", "")
+	statement.FieldsNames = []string{fieldCommitId, fieldNotesRef}
+	err = planInsertStatement(&env, statement, repo, newMutationPlan(), notesOptions)
+	assert.NotNil(t, err)
+}
+
+func TestPlanUpdateNotes(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+
+	plan := newMutationPlan()
+	_ = planInsertStatement(&env, notesInsertStatement("HEAD", "Build: passed"), repo, plan, notesOptions)
+	_ = applyReferenceChanges(plan.changes)
+	oldRef, _ := repo.Storer.Reference(defaultNotesRef)
+
+	statement := ast.UpdateStatement{
+		TableName:    notesTable,
+		FieldsNames:  []string{fieldNote},
+		FieldsValues: []ast.Expression{&ast.StringExpression{Value: "Build: failed", ValueType: ast.StringValueText}},
+	}
+
+	plan = newMutationPlan()
+	err := planUpdateStatement(&env, &statement, repo, plan, notesOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, "update", plan.changes[0].action())
+
+	err = applyReferenceChanges(plan.changes)
+	assert.Nil(t, err)
+	assert.Equal(t, map[plumbing.Hash]string{head.Hash(): "Build: failed\n"}, readNotes(t, repo, defaultNotesRef))
+
+	// The notes commit follows the previous notes commit
+	newRef, _ := repo.Storer.Reference(defaultNotesRef)
+	commit, _ := repo.CommitObject(newRef.Hash())
+	assert.Equal(t, []plumbing.Hash{oldRef.Hash()}, commit.ParentHashes)
+
+	// Moving a note to another notes reference removes it from the old one
+	statement.FieldsNames = []string{fieldNotesRef}
+	statement.FieldsValues = []ast.Expression{&ast.StringExpression{Value: "ci", ValueType: ast.StringValueText}}
+
+	plan = newMutationPlan()
+	err = planUpdateStatement(&env, &statement, repo, plan, notesOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(plan.changes))
+	assert.Equal(t, int64(1), plan.rows)
+
+	err = applyReferenceChanges(plan.changes)
+	assert.Nil(t, err)
+	assert.Equal(t, map[plumbing.Hash]string{}, readNotes(t, repo, defaultNotesRef))
+	assert.Equal(t, map[plumbing.Hash]string{head.Hash(): "Build: failed\n"}, readNotes(t, repo, "refs/notes/ci"))
+
+	// Updating to an unknown revision fails
+	statement.FieldsNames = []string{fieldCommitId}
+	statement.FieldsValues = []ast.Expression{&ast.StringExpression{Value: "unknown", ValueType: ast.StringValueText}}
+	err = planUpdateStatement(&env, &statement, repo, newMutationPlan(), notesOptions)
+	assert.NotNil(t, err)
+}
+
+func TestPlanDeleteNotes(t *testing.T) {
+	env := newMutationEnv()
+
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	plan := newMutationPlan()
+	_ = planInsertStatement(&env, notesInsertStatement("HEAD", "Build: passed"), repo, plan, notesOptions)
+	_ = applyReferenceChanges(plan.changes)
+
+	statement := ast.DeleteStatement{TableName: notesTable}
+
+	plan = newMutationPlan()
+	err := planDeleteStatement(&env, &statement, repo, plan, notesOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(plan.changes))
+	assert.Equal(t, int64(1), plan.rows)
+
+	err = applyReferenceChanges(plan.changes)
+	assert.Nil(t, err)
+	assert.Equal(t, map[plumbing.Hash]string{}, readNotes(t, repo, defaultNotesRef))
+
+	// Deleting without notes changes nothing
+	plan = newMutationPlan()
+	err = planDeleteStatement(&env, &statement, repo, plan, notesOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(plan.changes))
+}
+
+func TestNotesPlanSet(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+	note := "note\n"
+
+	notes := newNotesPlan(repo)
+	exists, err := notes.hasNote(defaultNotesRef, head.Hash())
+	assert.Nil(t, err)
+	assert.False(t, exists)
+
+	err = notes.set(defaultNotesRef, head.Hash(), &note)
+	assert.Nil(t, err)
+
+	exists, err = notes.hasNote(defaultNotesRef, head.Hash())
+	assert.Nil(t, err)
+	assert.True(t, exists)
+
+	// Changing the same note twice fails
+	err = notes.set(defaultNotesRef, head.Hash(), nil)
+	assert.NotNil(t, err)
+}
+
+func TestReadNotesTree(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	head, _ := repo.Head()
+	commitId := head.Hash().String()
+
+	blobHash := storeBlob(repo, "note\n")
+	fanoutHash := storeObject(repo, &object.Tree{Entries: []object.TreeEntry{
+		{Name: commitId[2:], Mode: filemode.Regular, Hash: blobHash},
+	}})
+	treeHash := storeObject(repo, &object.Tree{Entries: []object.TreeEntry{
+		{Name: ".gitattributes", Mode: filemode.Regular, Hash: blobHash},
+		{Name: commitId[:2], Mode: filemode.Dir, Hash: fanoutHash},
+	}})
+	tree, _ := repo.TreeObject(treeHash)
+
+	notes := map[plumbing.Hash]plumbing.Hash{}
+	others, err := readNotesTree(tree, "", notes)
+	assert.Nil(t, err)
+	assert.Equal(t, map[plumbing.Hash]plumbing.Hash{head.Hash(): blobHash}, notes)
+	assert.Equal(t, 1, len(others))
+	assert.Equal(t, ".gitattributes", others[0].Name)
+}
+
+func TestTreeEntrySortName(t *testing.T) {
+	assert.Equal(t, "a/", treeEntrySortName(object.TreeEntry{Name: "a", Mode: filemode.Dir}))
+	assert.Equal(t, "a", treeEntrySortName(object.TreeEntry{Name: "a", Mode: filemode.Regular}))
+}
+
+func TestNewBlobObject(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	encoded, err := newBlobObject(repo, "note\n")
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.BlobObject, encoded.Type())
+	assert.Equal(t, int64(5), encoded.Size())
+}
+
+func TestNotesReferenceName(t *testing.T) {
+	name, err := notesReferenceName("")
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ReferenceName(defaultNotesRef), name)
+
+	name, err = notesReferenceName("ci")
+	assert.Nil(t, err)
+	assert.Equal(t, plumbing.ReferenceName("refs/notes/ci"), name)
+
+	_, err = notesReferenceName("refs/heads/main")
+	assert.NotNil(t, err)
+
+	_, err = notesReferenceName("ci..")
+	assert.NotNil(t, err)
+}
+
+func TestNoteContent(t *testing.T) {
+	assert.Equal(t, "note\n", noteContent(ast.TextValue{Value: "note"}))
+	assert.Equal(t, "note\n", noteContent(ast.TextValue{Value: "note\n"}))
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	reflogDirectory = "logs"
+	reflogHashSize  = 40
+	stashRef        = "refs/stash"
+)
+
+var (
+	stashBranchPattern = regexp.MustCompile("^(?:WIP on|On) (.+?): ")
+)
+
+// reflogEntry is a line of the reflog file of a reference
+type reflogEntry struct {
+	oldId     string
+	newId     string
+	signature object.Signature
+	message   string
+}
+
+// reflogRefs returns the sorted names of the references which have a reflog file in the logs directory
+func reflogRefs(fs billy.Filesystem) ([]string, error) {
+	var refs []string
+	err := util.Walk(fs, reflogDirectory, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			refs = append(refs, strings.TrimPrefix(filepath.ToSlash(filePath), reflogDirectory+"/"))
+		}
+		return nil
+	})
+	sort.Strings(refs)
+	return refs, err
+}
+
+// readReflog returns the entries of the reflog of the reference, the newest entry is the first one like `ref@{0}`
+func readReflog(fs billy.Filesystem, ref string) ([]reflogEntry, error) {
+	file, err := fs.Open(fs.Join(reflogDirectory, ref))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := parseReflog(string(content))
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// parseReflog returns the entries of the reflog content in the file order, the invalid lines are skipped
+func parseReflog(content string) []reflogEntry {
+	var entries []reflogEntry
+	for _, line := range strings.Split(content, "\n") {
+		if entry, ok := parseReflogLine(line); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseReflogLine parses a reflog line `<old id> <new id> <name> <<email>> <timestamp> <timezone>\t<message>`
+func parseReflogLine(line string) (reflogEntry, bool) {
+	var entry reflogEntry
+
+	header, message, _ := strings.Cut(line, "\t")
+	if len(header) <= 2*reflogHashSize+2 || header[reflogHashSize] != ' ' || header[2*reflogHashSize+1] != ' ' {
+		return entry, false
+	}
+
+	entry.oldId = header[:reflogHashSize]
+	entry.newId = header[reflogHashSize+1 : 2*reflogHashSize+1]
+	entry.signature.Decode([]byte(header[2*reflogHashSize+2:]))
+	entry.message = message
+	return entry, true
+}
+
+// stashBranch returns the branch of the stash message like `WIP on <branch>: <commit>` or `On <branch>: <message>`
+func stashBranch(message string) string {
+	matches := stashBranchPattern.FindStringSubmatch(message)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	reflogOldId = "0000000000000000000000000000000000000000"
+	reflogNewId = "1111111111111111111111111111111111111111"
+	reflogLine  = reflogOldId + " " + reflogNewId + " name <name@example.com> 1700000000 +0100\tcommit (initial): Initial"
+)
+
+func TestReflogRefs(t *testing.T) {
+	fs := memfs.New()
+
+	refs, err := reflogRefs(fs)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(refs))
+
+	_ = util.WriteFile(fs, "logs/refs/heads/main", []byte(reflogLine), 0o644)
+	_ = util.WriteFile(fs, "logs/HEAD", []byte(reflogLine), 0o644)
+
+	refs, err = reflogRefs(fs)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"HEAD", "refs/heads/main"}, refs)
+}
+
+func TestReadReflog(t *testing.T) {
+	fs := memfs.New()
+	_ = util.WriteFile(fs, "logs/HEAD", []byte(reflogLine+"\n"+reflogNewId+" "+reflogOldId+" name <name@example.com> 1700000100 +0100\treset: moving to HEAD~1\n"), 0o644)
+
+	entries, err := readReflog(fs, "HEAD")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "reset: moving to HEAD~1", entries[0].message)
+	assert.Equal(t, "commit (initial): Initial", entries[1].message)
+
+	_, err = readReflog(fs, "refs/heads/main")
+	assert.NotNil(t, err)
+}
+
+func TestParseReflog(t *testing.T) {
+	entries := parseReflog(reflogLine + "\ninvalid\n\n" + reflogLine + "\n")
+	assert.Equal(t, 2, len(entries))
+}
+
+func TestParseReflogLine(t *testing.T) {
+	entry, ok := parseReflogLine(reflogLine)
+	assert.True(t, ok)
+	assert.Equal(t, reflogOldId, entry.oldId)
+	assert.Equal(t, reflogNewId, entry.newId)
+	assert.Equal(t, "name", entry.signature.Name)
+	assert.Equal(t, "name@example.com", entry.signature.Email)
+	assert.Equal(t, int64(1700000000), entry.signature.When.Unix())
+	assert.Equal(t, "commit (initial): Initial", entry.message)
+
+	// Entry without message
+	entry, ok = parseReflogLine(reflogOldId + " " + reflogNewId + " name <name@example.com> 1700000000 +0100")
+	assert.True(t, ok)
+	assert.Equal(t, "", entry.message)
+
+	_, ok = parseReflogLine("")
+	assert.False(t, ok)
+
+	_, ok = parseReflogLine(reflogOldId + reflogNewId + " name <name@example.com> 1700000000 +0100\tmessage")
+	assert.False(t, ok)
+}
+
+func TestStashBranch(t *testing.T) {
+	assert.Equal(t, "main", stashBranch("WIP on main: 1234567 Adding"))
+	assert.Equal(t, "feature/a", stashBranch("On feature/a: Experiment: first"))
+	assert.Equal(t, "", stashBranch("Experiment"))
+}
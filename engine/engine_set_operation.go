@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/ggql/ggql/ast"
+)
+
+// setOperationsStatements are applied on the combined rows of the set operations queries
+var setOperationsStatements = []string{"order", "offset", "limit"}
+
+// evaluateSetOperations combines the selected rows of the queries in order, `INTERSECT` is applied before `UNION`
+// and `EXCEPT` like in SQL, then the `ORDER BY`, `OFFSET` and `LIMIT` statements are applied on the combined rows
+func evaluateSetOperations(
+	env *ast.Environment,
+	repos []*git.Repository,
+	query ast.GQLQuery,
+) (EvaluationResult, error) {
+	firstQuery := query
+	firstQuery.SetOperations = nil
+	firstQuery.Statements = make(map[string]ast.Statement)
+	for statementName, statement := range query.Statements {
+		if !contains(setOperationsStatements, statementName) {
+			firstQuery.Statements[statementName] = statement
+		}
+	}
+
+	result, err := EvaluateSelectQuery(env, repos, firstQuery)
+	if err != nil {
+		return EvaluationResult{}, err
+	}
+
+	_, firstRows := selectedRows(result)
+	operands := [][][]ast.Value{firstRows}
+	var operations []ast.SetOperation
+
+	for _, operation := range query.SetOperations {
+		result, err := EvaluateSelectQuery(operation.Env, repos, *operation.Query)
+		if err != nil {
+			return EvaluationResult{}, err
+		}
+
+		_, rows := selectedRows(result)
+		if operation.Operator == ast.SOIntersect {
+			last := len(operands) - 1
+			operands[last] = combineRows(operation, operands[last], rows)
+			continue
+		}
+
+		operands = append(operands, rows)
+		operations = append(operations, operation)
+	}
+
+	rows := operands[0]
+	for index, operation := range operations {
+		rows = combineRows(operation, rows, operands[index+1])
+	}
+
+	// The combined rows are named by the fields of the first query
+	selectStatement := query.Statements["select"].(*ast.SelectStatement)
+	titles := make([]string, 0, len(selectStatement.FieldsNames))
+	for _, fieldName := range selectStatement.FieldsNames {
+		titles = append(titles, GetColumnName(selectStatement.AliasTable, fieldName))
+	}
+
+	group := ast.Group{Rows: make([]ast.Row, 0, len(rows))}
+	for _, values := range rows {
+		group.Rows = append(group.Rows, ast.Row{Values: values})
+	}
+
+	gitqlObject := ast.GitQLObject{Titles: titles, Groups: []ast.Group{group}}
+	for _, statementName := range setOperationsStatements {
+		if statement, ok := query.Statements[statementName]; ok {
+			err := ExecuteStatement(env, statement, repos[0], &gitqlObject, make(map[string]string), nil)
+			if err != nil {
+				return EvaluationResult{}, err
+			}
+		}
+	}
+
+	return EvaluationResult{SelectedGroups: struct {
+		Obj ast.GitQLObject
+		Str []string
+	}{
+		Obj: gitqlObject,
+		Str: nil,
+	},
+	}, nil
+}
+
+// combineRows applies the set operation on the rows, without `ALL` the duplicated rows are removed
+func combineRows(operation ast.SetOperation, left, right [][]ast.Value) [][]ast.Value {
+	var rows [][]ast.Value
+
+	switch operation.Operator {
+	case ast.SOUnion:
+		rows = make([][]ast.Value, 0, len(left)+len(right))
+		rows = append(rows, left...)
+		rows = append(rows, right...)
+	default:
+		// With `ALL` each right row matches only one left row
+		counts := make(map[string]int)
+		for _, row := range right {
+			counts[rowKey(row)]++
+		}
+
+		for _, row := range left {
+			key := rowKey(row)
+			isInRight := counts[key] > 0
+			if isInRight && operation.IsAll {
+				counts[key]--
+			}
+
+			if isInRight == (operation.Operator == ast.SOIntersect) {
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	if operation.IsAll {
+		return rows
+	}
+
+	return distinctRows(rows)
+}
+
+// distinctRows removes the duplicated rows and keeps the first one
+func distinctRows(rows [][]ast.Value) [][]ast.Value {
+	keys := make(map[string]bool)
+	distinct := make([][]ast.Value, 0, len(rows))
+	for _, row := range rows {
+		key := rowKey(row)
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		distinct = append(distinct, row)
+	}
+	return distinct
+}
+
+func rowKey(values []ast.Value) string {
+	keys := make([]string, 0, len(values))
+	for _, value := range values {
+		keys = append(keys, value.DataType().Fmt()+":"+value.Fmt())
+	}
+	return strings.Join(keys, "\x00")
+}
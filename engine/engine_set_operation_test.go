@@ -0,0 +1,77 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+)
+
+func TestEvaluateSetOperations(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `SELECT name AS n FROM branches UNION ALL SELECT full_name FROM refs EXCEPT SELECT "HEAD" ORDER BY n LIMIT 1`)
+
+	result, err := EvaluateSelectQuery(&env, []*git.Repository{repo}, *query)
+	assert.Nil(t, err)
+
+	titles, rows := selectedRows(result)
+	assert.Equal(t, []string{"n"}, titles)
+	assert.Equal(t, [][]ast.Value{{ast.TextValue{Value: "refs/heads/feature"}}}, rows)
+
+	// `INTERSECT` is applied before `EXCEPT`
+	query = parseSelectQuery(t, &env, `SELECT full_name FROM refs EXCEPT SELECT "HEAD" INTERSECT SELECT full_name FROM refs`)
+
+	result, err = evaluateSetOperations(&env, []*git.Repository{repo}, *query)
+	assert.Nil(t, err)
+
+	_, rows = selectedRows(result)
+	assert.Equal(t, 2, len(rows))
+}
+
+func TestCombineRows(t *testing.T) {
+	one := []ast.Value{ast.IntegerValue{Value: 1}}
+	two := []ast.Value{ast.IntegerValue{Value: 2}}
+	three := []ast.Value{ast.IntegerValue{Value: 3}}
+
+	left := [][]ast.Value{one, one, two}
+	right := [][]ast.Value{one, three}
+
+	union := ast.SetOperation{Operator: ast.SOUnion}
+	assert.Equal(t, [][]ast.Value{one, two, three}, combineRows(union, left, right))
+
+	union.IsAll = true
+	assert.Equal(t, [][]ast.Value{one, one, two, one, three}, combineRows(union, left, right))
+
+	intersect := ast.SetOperation{Operator: ast.SOIntersect}
+	assert.Equal(t, [][]ast.Value{one}, combineRows(intersect, left, right))
+
+	intersect.IsAll = true
+	assert.Equal(t, [][]ast.Value{one}, combineRows(intersect, left, right))
+
+	except := ast.SetOperation{Operator: ast.SOExcept}
+	assert.Equal(t, [][]ast.Value{two}, combineRows(except, left, right))
+
+	except.IsAll = true
+	assert.Equal(t, [][]ast.Value{one, two}, combineRows(except, left, right))
+}
+
+func TestDistinctRows(t *testing.T) {
+	rows := [][]ast.Value{
+		{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 1}},
+		{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 2}},
+		{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 1}},
+	}
+
+	assert.Equal(t, rows[:2], distinctRows(rows))
+}
+
+func TestRowKey(t *testing.T) {
+	assert.Equal(t, rowKey([]ast.Value{ast.TextValue{Value: "a"}}), rowKey([]ast.Value{ast.TextValue{Value: "a"}}))
+	assert.NotEqual(t, rowKey([]ast.Value{ast.TextValue{Value: "1"}}), rowKey([]ast.Value{ast.IntegerValue{Value: 1}}))
+	assert.NotEqual(t, rowKey([]ast.Value{ast.TextValue{Value: "a"}, ast.TextValue{Value: "b"}}), rowKey([]ast.Value{ast.TextValue{Value: "ab"}}))
+}
@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"errors"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+
+	"github.com/ggql/ggql/ast"
+)
+
+// evaluateSubqueries evaluates each subquery once on all repositories and keeps the selected values
+func evaluateSubqueries(repos []*git.Repository, subqueries []*ast.SubqueryExpression) error {
+	for _, subquery := range subqueries {
+		result, err := EvaluateSelectQuery(subquery.Env, repos, *subquery.Query)
+		if err != nil {
+			return err
+		}
+
+		_, rows := selectedRows(result)
+		if subquery.IsScalar && len(rows) > 1 {
+			return errors.New("subquery used as a value returns more than one row")
+		}
+
+		subquery.Values = make([]ast.Value, 0, len(rows))
+		for _, row := range rows {
+			subquery.Values = append(subquery.Values, row[0])
+		}
+		subquery.IsEvaluated = true
+	}
+
+	return nil
+}
+
+// selectedRows returns the titles and the rows values of the selected fields without the hidden selections
+func selectedRows(result EvaluationResult) ([]string, [][]ast.Value) {
+	gitqlObject := result.SelectedGroups.Obj
+	if gitqlObject.IsEmpty() {
+		return nil, nil
+	}
+
+	if gitqlObject.Len() > 1 {
+		gitqlObject.Flat()
+	}
+
+	var titles []string
+	var indexes []int
+	for index, title := range gitqlObject.Titles {
+		if !contains(result.SelectedGroups.Str, title) {
+			titles = append(titles, title)
+			indexes = append(indexes, index)
+		}
+	}
+
+	rows := make([][]ast.Value, 0, len(gitqlObject.Groups[0].Rows))
+	for _, row := range gitqlObject.Groups[0].Rows {
+		values := make([]ast.Value, 0, len(indexes))
+		for _, index := range indexes {
+			values = append(values, row.Values[index])
+		}
+		rows = append(rows, values)
+	}
+
+	return titles, rows
+}
+
+func EvaluateSubquery(expr *ast.SubqueryExpression) (ast.Value, error) {
+	if !expr.IsEvaluated {
+		return nil, errors.New("subquery is not evaluated")
+	}
+
+	switch len(expr.Values) {
+	case 0:
+		return ast.NullValue{}, nil
+	case 1:
+		return expr.Values[0], nil
+	default:
+		return nil, errors.New("subquery used as a value returns more than one row")
+	}
+}
+
+// selectDerivedTable selects the fields from the rows of the derived table query evaluated on the repository
+func selectDerivedTable(
+	env *ast.Environment,
+	repo *git.Repository,
+	table *ast.DerivedTable,
+	fieldsNames []string,
+	titles []string,
+	fieldsValues []ast.Expression,
+) (*ast.Group, error) {
+	var rows []ast.Row
+
+	derivedTitles, derivedRows, err := derivedTableRows(table, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	namesLen := int64(len(fieldsNames))
+	valuesLen := int64(len(fieldsValues))
+	padding := namesLen - valuesLen
+
+	for _, derivedRow := range derivedRows {
+		var values []ast.Value
+		for index := int64(0); index < namesLen; index++ {
+			fieldName := fieldsNames[index]
+			if index-padding >= 0 {
+				value := fieldsValues[index-padding]
+				if _, ok := value.(*ast.SymbolExpression); !ok {
+					evaluated, err := EvaluateExpression(env, value, titles, values)
+					if err != nil {
+						return nil, err
+					}
+					values = append(values, evaluated)
+					continue
+				}
+			}
+			if fieldIndex := indexOf(derivedTitles, fieldName); fieldIndex != -1 {
+				values = append(values, derivedRow[fieldIndex])
+			} else {
+				values = append(values, ast.NullValue{})
+			}
+		}
+		rows = append(rows, ast.Row{Values: values})
+	}
+
+	return &ast.Group{Rows: rows}, nil
+}
+
+// derivedTableRows evaluates the derived table query on the repository, the result of a common table expression is
+// kept so the query is evaluated once for each repository even if the table is used more than once
+func derivedTableRows(table *ast.DerivedTable, repo *git.Repository) ([]string, [][]ast.Value, error) {
+	var repoPath string
+	if table.IsCommon {
+		storer, _ := repo.Storer.(*filesystem.Storage)
+		repoPath = storer.Filesystem().Root()
+		if gitqlObject, ok := table.Results[repoPath]; ok {
+			titles, rows := gitqlObjectRows(gitqlObject)
+			return titles, rows, nil
+		}
+	}
+
+	result, err := EvaluateSelectQuery(table.Env, []*git.Repository{repo}, *table.Query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	titles, rows := selectedRows(result)
+
+	if table.IsCommon {
+		gitqlObject := &ast.GitQLObject{Titles: titles, Groups: []ast.Group{{}}}
+		for _, values := range rows {
+			gitqlObject.Groups[0].Rows = append(gitqlObject.Groups[0].Rows, ast.Row{Values: values})
+		}
+
+		if table.Results == nil {
+			table.Results = make(map[string]*ast.GitQLObject)
+		}
+		table.Results[repoPath] = gitqlObject
+	}
+
+	return titles, rows, nil
+}
+
+func gitqlObjectRows(gitqlObject *ast.GitQLObject) ([]string, [][]ast.Value) {
+	rows := make([][]ast.Value, 0, len(gitqlObject.Groups[0].Rows))
+	for _, row := range gitqlObject.Groups[0].Rows {
+		rows = append(rows, row.Values)
+	}
+	return gitqlObject.Titles, rows
+}
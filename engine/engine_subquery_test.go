@@ -0,0 +1,138 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+	"github.com/ggql/ggql/parser"
+)
+
+func parseSelectQuery(t *testing.T, env *ast.Environment, query string) *ast.GQLQuery {
+	tokens, tokenizeErr := parser.Tokenize(query)
+	assert.Equal(t, "", tokenizeErr.Message)
+
+	parsed, parseErr := parser.ParserGql(tokens, env)
+	assert.Equal(t, "", parseErr.Message)
+
+	return parsed.Select
+}
+
+func TestEvaluateSubqueries(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `SELECT name FROM branches WHERE name IN (SELECT name FROM branches WHERE name LIKE "%feature")`)
+	assert.Equal(t, 1, len(query.Subqueries))
+
+	err := evaluateSubqueries([]*git.Repository{repo}, query.Subqueries)
+	assert.Nil(t, err)
+	assert.Equal(t, true, query.Subqueries[0].IsEvaluated)
+	assert.Equal(t, []ast.Value{ast.TextValue{Value: "refs/heads/feature"}}, query.Subqueries[0].Values)
+
+	// Scalar subqueries can't select more than one row
+	query = parseSelectQuery(t, &env, `SELECT (SELECT name FROM branches)`)
+
+	err = evaluateSubqueries([]*git.Repository{repo}, query.Subqueries)
+	assert.Equal(t, "subquery used as a value returns more than one row", err.Error())
+}
+
+func TestSelectedRows(t *testing.T) {
+	result := EvaluationResult{}
+	result.SelectedGroups.Obj = ast.GitQLObject{
+		Titles: []string{"hidden", "title"},
+		Groups: []ast.Group{
+			{Rows: []ast.Row{{Values: []ast.Value{ast.IntegerValue{Value: 1}, ast.TextValue{Value: "one"}}}}},
+			{Rows: []ast.Row{{Values: []ast.Value{ast.IntegerValue{Value: 2}, ast.TextValue{Value: "two"}}}}},
+		},
+	}
+	result.SelectedGroups.Str = []string{"hidden"}
+
+	titles, rows := selectedRows(result)
+	assert.Equal(t, []string{"title"}, titles)
+	assert.Equal(t, [][]ast.Value{{ast.TextValue{Value: "one"}}, {ast.TextValue{Value: "two"}}}, rows)
+
+	titles, rows = selectedRows(EvaluationResult{})
+	assert.Nil(t, titles)
+	assert.Nil(t, rows)
+}
+
+func TestEvaluateSubquery(t *testing.T) {
+	subquery := &ast.SubqueryExpression{}
+
+	_, err := EvaluateSubquery(subquery)
+	assert.Equal(t, "subquery is not evaluated", err.Error())
+
+	subquery.IsEvaluated = true
+	value, err := EvaluateSubquery(subquery)
+	assert.Nil(t, err)
+	assert.Equal(t, ast.NullValue{}, value)
+
+	subquery.Values = []ast.Value{ast.IntegerValue{Value: 1}}
+	value, err = EvaluateSubquery(subquery)
+	assert.Nil(t, err)
+	assert.Equal(t, ast.IntegerValue{Value: 1}, value)
+}
+
+func TestSelectDerivedTable(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `SELECT b.n, b.c FROM (SELECT name AS n, commit_count AS c FROM branches WHERE name LIKE "%master") AS b`)
+	table := query.Statements["select"].(*ast.SelectStatement).DerivedTable
+	assert.Equal(t, []string{"n", "c"}, table.FieldsNames)
+
+	group, err := selectDerivedTable(&env, repo, table, []string{"c", "missing"}, []string{"c", "missing"}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(group.Rows))
+	assert.Equal(t, []ast.Value{ast.IntegerValue{Value: 1}, ast.NullValue{}}, group.Rows[0].Values)
+}
+
+func TestDerivedTableRows(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `WITH b AS (SELECT name AS n FROM branches) SELECT x.n FROM b x JOIN b y ON x.n = y.n`)
+	table := query.Statements["select"].(*ast.SelectStatement).DerivedTable
+	assert.Equal(t, true, table.IsCommon)
+
+	titles, rows, err := derivedTableRows(table, repo)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"n"}, titles)
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, 1, len(table.Results))
+
+	// The result of the common table is used instead of evaluating the query again
+	for _, gitqlObject := range table.Results {
+		gitqlObject.Groups[0].Rows = gitqlObject.Groups[0].Rows[:1]
+	}
+
+	_, rows, err = derivedTableRows(table, repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+
+	// Derived tables are evaluated each time
+	query = parseSelectQuery(t, &env, `SELECT n FROM (SELECT name AS n FROM branches) AS b`)
+	table = query.Statements["select"].(*ast.SelectStatement).DerivedTable
+
+	_, rows, err = derivedTableRows(table, repo)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rows))
+	assert.Nil(t, table.Results)
+}
+
+func TestGitqlObjectRows(t *testing.T) {
+	gitqlObject := &ast.GitQLObject{
+		Titles: []string{"n"},
+		Groups: []ast.Group{{Rows: []ast.Row{{Values: []ast.Value{ast.IntegerValue{Value: 1}}}}}},
+	}
+
+	titles, rows := gitqlObjectRows(gitqlObject)
+	assert.Equal(t, []string{"n"}, titles)
+	assert.Equal(t, [][]ast.Value{{ast.IntegerValue{Value: 1}}}, rows)
+}
@@ -75,17 +75,111 @@ func TestEvaluateSelectQuery(t *testing.T) {
 		t.Fatal("failed to parser")
 	}
 
-	switch query {
-	case ast.Query{Select: query.Select}:
+	if query.Select != nil {
 		_, err := Evaluate(&env, repos, query)
 		if err != nil {
 			t.Fatal("failed to delete repo:", err)
 		}
-	default:
-		// PASS
 	}
 }
 
+func TestEvaluateSelectQueryWithGroupBy(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `SELECT is_remote, commit_count AS commits, count(name) AS c FROM branches GROUP BY is_remote, commits`)
+
+	result, err := EvaluateSelectQuery(&env, []*git.Repository{repo}, *query)
+	assert.Nil(t, err)
+
+	titles, rows := selectedRows(result)
+	assert.Equal(t, []string{"is_remote", "commits", "c"}, titles)
+	assert.Equal(t, [][]ast.Value{{ast.BooleanValue{Value: false}, ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 2}}}, rows)
+
+	// Group by an expression of a field which is not selected
+	query = parseSelectQuery(t, &env, `SELECT count(name) AS c FROM branches GROUP BY lower(name) HAVING c = 1 ORDER BY c`)
+
+	result, err = EvaluateSelectQuery(&env, []*git.Repository{repo}, *query)
+	assert.Nil(t, err)
+
+	_, rows = selectedRows(result)
+	assert.Equal(t, [][]ast.Value{{ast.IntegerValue{Value: 1}}, {ast.IntegerValue{Value: 1}}}, rows)
+}
+
+func TestEvaluateSelectQueryWithUnknownRevision(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	otherRepo := newFunctionRepo()
+	defer deleteFunctionRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `SELECT path FROM files WHERE revision = "feature"`)
+
+	// The repository without the revision is skipped
+	result, err := EvaluateSelectQuery(&env, []*git.Repository{otherRepo, repo}, *query)
+	assert.Nil(t, err)
+
+	_, rows := selectedRows(result)
+	assert.Equal(t, [][]ast.Value{{ast.TextValue{Value: mutationFile}}}, rows)
+
+	// The revision must be in one of the repositories
+	_, err = EvaluateSelectQuery(&env, []*git.Repository{otherRepo}, *query)
+	assert.Equal(t, "unknown revision feature", err.Error())
+}
+
+func TestEvaluateSelectQueryWithWindowFunctions(t *testing.T) {
+	repo := newMutationRepo()
+	defer deleteMutationRepo()
+
+	env := newMutationEnv()
+	query := parseSelectQuery(t, &env, `SELECT name, row_number() OVER (ORDER BY name DESC) AS n, count(name) OVER () AS total FROM branches ORDER BY n`)
+
+	result, err := EvaluateSelectQuery(&env, []*git.Repository{repo}, *query)
+	assert.Nil(t, err)
+
+	titles, rows := selectedRows(result)
+	assert.Equal(t, []string{"name", "n", "total"}, titles)
+	assert.Equal(t, [][]ast.Value{
+		{ast.TextValue{Value: "refs/heads/master"}, ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 2}},
+		{ast.TextValue{Value: "refs/heads/feature"}, ast.IntegerValue{Value: 2}, ast.IntegerValue{Value: 2}},
+	}, rows)
+
+	// Window functions are evaluated over the groups
+	query = parseSelectQuery(t, &env, `SELECT commit_count, rank() OVER (ORDER BY count(name) DESC) AS r FROM branches GROUP BY commit_count`)
+
+	result, err = EvaluateSelectQuery(&env, []*git.Repository{repo}, *query)
+	assert.Nil(t, err)
+
+	_, rows = selectedRows(result)
+	assert.Equal(t, [][]ast.Value{{ast.IntegerValue{Value: 1}, ast.IntegerValue{Value: 1}}}, rows)
+}
+
+func TestApplyGroupsFirstRow(t *testing.T) {
+	object := ast.GitQLObject{
+		Titles: []string{"id"},
+		Groups: []ast.Group{
+			{Rows: []ast.Row{{Values: []ast.Value{ast.IntegerValue{Value: 1}}}, {Values: []ast.Value{ast.IntegerValue{Value: 2}}}}},
+			{Rows: []ast.Row{{Values: []ast.Value{ast.IntegerValue{Value: 3}}}}},
+		},
+	}
+
+	ApplyGroupsFirstRow(&object)
+	assert.Equal(t, 1, len(object.Groups[0].Rows))
+	assert.Equal(t, ast.IntegerValue{Value: 1}, object.Groups[0].Rows[0].Values[0])
+	assert.Equal(t, 1, len(object.Groups[1].Rows))
+}
+
+func TestApplySelectedFieldsAliases(t *testing.T) {
+	object := ast.GitQLObject{
+		Titles: []string{"name", "commit_count"},
+	}
+
+	ApplySelectedFieldsAliases(&object, map[string]string{"commit_count": "count"})
+	assert.Equal(t, []string{"name", "count"}, object.Titles)
+}
+
 func TestApplyDistinctOnObjectsGroup(t *testing.T) {
 	object := ast.GitQLObject{
 		Titles: []string{"title1", "title2"},
@@ -0,0 +1,227 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ggql/ggql/ast"
+)
+
+// windowRows is the rows indexes of a window partition sorted by the window order, with the range of peers of each row
+type windowRows struct {
+	indexes    []int
+	peersStart []int
+	peersEnd   []int
+}
+
+// executeWindowFunctionsStatement computes the window functions columns first, then the expressions which use them
+// in the order they are generated
+func executeWindowFunctionsStatement(
+	env *ast.Environment,
+	statement *ast.WindowFunctionsStatement,
+	gitqlObject *ast.GitQLObject,
+	aliasTable map[string]string,
+) error {
+	if gitqlObject.IsEmpty() {
+		return nil
+	}
+
+	if gitqlObject.Len() > 1 {
+		gitqlObject.Flat()
+	}
+
+	mainGroup := &gitqlObject.Groups[0]
+	if mainGroup.IsEmpty() {
+		return nil
+	}
+
+	titles := windowTitles(gitqlObject.Titles, aliasTable)
+
+	columnsNames := make([]string, 0, len(statement.Windows))
+	for columnName := range statement.Windows {
+		columnsNames = append(columnsNames, columnName)
+	}
+	sortGeneratedColumnsNames(columnsNames)
+
+	for _, columnName := range columnsNames {
+		window := statement.Windows[columnName]
+		if window.Function == nil {
+			continue
+		}
+
+		columnIndex := indexOf(titles, columnName)
+		if columnIndex == -1 {
+			return fmt.Errorf("invalid window column name %s", columnName)
+		}
+
+		if err := executeWindowFunction(env, window.Function, titles, mainGroup.Rows, columnIndex); err != nil {
+			return err
+		}
+	}
+
+	for _, columnName := range columnsNames {
+		window := statement.Windows[columnName]
+		if window.Function != nil {
+			continue
+		}
+
+		columnIndex := indexOf(titles, columnName)
+		if columnIndex == -1 {
+			return fmt.Errorf("invalid window column name %s", columnName)
+		}
+
+		for index := range mainGroup.Rows {
+			row := &mainGroup.Rows[index]
+			value, err := EvaluateExpression(env, window.Expression, titles, row.Values)
+			if err != nil {
+				return err
+			}
+			row.Values[columnIndex] = value
+		}
+	}
+
+	return nil
+}
+
+// windowTitles returns the fields names of the titles, the window functions are executed after the titles are renamed
+// to the selected fields aliases
+func windowTitles(titles []string, aliasTable map[string]string) []string {
+	fieldsNames := make(map[string]string, len(aliasTable))
+	for fieldName, alias := range aliasTable {
+		fieldsNames[alias] = fieldName
+	}
+
+	windowTitles := make([]string, 0, len(titles))
+	for _, title := range titles {
+		if fieldName, ok := fieldsNames[title]; ok {
+			windowTitles = append(windowTitles, fieldName)
+		} else {
+			windowTitles = append(windowTitles, title)
+		}
+	}
+	return windowTitles
+}
+
+// executeWindowFunction stores the window function result of each row in the column
+func executeWindowFunction(
+	env *ast.Environment,
+	function *ast.WindowFunctionCall,
+	titles []string,
+	rows []ast.Row,
+	columnIndex int,
+) error {
+	partitions, err := windowPartitions(env, function.Window, titles, rows)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		arguments := make([][]ast.Value, 0, len(partition.indexes))
+		for _, rowIndex := range partition.indexes {
+			values := make([]ast.Value, 0, len(function.Arguments))
+			for _, argument := range function.Arguments {
+				value, err := EvaluateExpression(env, argument, titles, rows[rowIndex].Values)
+				if err != nil {
+					return err
+				}
+				values = append(values, value)
+			}
+			arguments = append(arguments, values)
+		}
+
+		values := ast.WindowFunctions[function.Name](&ast.WindowPartition{
+			Arguments:  arguments,
+			PeersStart: partition.peersStart,
+			PeersEnd:   partition.peersEnd,
+		})
+
+		for position, rowIndex := range partition.indexes {
+			rows[rowIndex].Values[columnIndex] = values[position]
+		}
+	}
+
+	return nil
+}
+
+// windowPartitions splits the rows by the `PARTITION BY` values in the order of their first rows, and sorts each
+// partition by the `ORDER BY` arguments, the rows with equal order values are peers
+func windowPartitions(env *ast.Environment, window ast.Window, titles []string, rows []ast.Row) ([]windowRows, error) {
+	var partitions []windowRows
+	partitionsIndexes := make(map[string]int)
+	orderValues := make([][]ast.Value, len(rows))
+
+	for rowIndex, row := range rows {
+		partitionValues := make([]ast.Value, 0, len(window.PartitionBy))
+		for _, expression := range window.PartitionBy {
+			value, err := EvaluateExpression(env, expression, titles, row.Values)
+			if err != nil {
+				return nil, err
+			}
+			partitionValues = append(partitionValues, value)
+		}
+
+		if window.OrderBy != nil {
+			for _, argument := range window.OrderBy.Arguments {
+				value, err := EvaluateExpression(env, argument, titles, row.Values)
+				if err != nil {
+					return nil, err
+				}
+				orderValues[rowIndex] = append(orderValues[rowIndex], value)
+			}
+		}
+
+		key := rowKey(partitionValues)
+		partitionIndex, ok := partitionsIndexes[key]
+		if !ok {
+			partitionIndex = len(partitions)
+			partitionsIndexes[key] = partitionIndex
+			partitions = append(partitions, windowRows{})
+		}
+		partitions[partitionIndex].indexes = append(partitions[partitionIndex].indexes, rowIndex)
+	}
+
+	var sortingOrders []ast.SortingOrder
+	if window.OrderBy != nil {
+		sortingOrders = window.OrderBy.SortingOrders
+	}
+
+	for index := range partitions {
+		partition := &partitions[index]
+		sort.SliceStable(partition.indexes, func(i, j int) bool {
+			return compareOrderValues(orderValues[partition.indexes[i]], orderValues[partition.indexes[j]], sortingOrders) < 0
+		})
+
+		count := len(partition.indexes)
+		partition.peersStart = make([]int, count)
+		partition.peersEnd = make([]int, count)
+		for position := 0; position < count; position++ {
+			partition.peersStart[position] = position
+			if position > 0 && compareOrderValues(orderValues[partition.indexes[position-1]], orderValues[partition.indexes[position]], sortingOrders) == 0 {
+				partition.peersStart[position] = partition.peersStart[position-1]
+			}
+		}
+		for position := count - 1; position >= 0; position-- {
+			partition.peersEnd[position] = position
+			if position < count-1 && partition.peersStart[position+1] == partition.peersStart[position] {
+				partition.peersEnd[position] = partition.peersEnd[position+1]
+			}
+		}
+	}
+
+	return partitions, nil
+}
+
+// compareOrderValues returns the ordering of the first row relative to the other row by the sorting orders
+func compareOrderValues(first, other []ast.Value, sortingOrders []ast.SortingOrder) int {
+	for index := range first {
+		ordering := compare(first[index], other[index])
+		if sortingOrders[index] == ast.Descending {
+			ordering = -ordering
+		}
+
+		if ordering != 0 {
+			return ordering
+		}
+	}
+	return 0
+}
@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ggql/ggql/ast"
+)
+
+func newWindowRows() []ast.Row {
+	return []ast.Row{
+		{Values: []ast.Value{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 3}, ast.NullValue{}, ast.NullValue{}}},
+		{Values: []ast.Value{ast.TextValue{Value: "b"}, ast.IntegerValue{Value: 1}, ast.NullValue{}, ast.NullValue{}}},
+		{Values: []ast.Value{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 1}, ast.NullValue{}, ast.NullValue{}}},
+		{Values: []ast.Value{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 1}, ast.NullValue{}, ast.NullValue{}}},
+	}
+}
+
+func TestExecuteWindowFunctionsStatement(t *testing.T) {
+	env := newMutationEnv()
+
+	// SELECT name, value, sum(value) OVER (PARTITION BY name ORDER BY value) AS running, running + 1
+	statement := &ast.WindowFunctionsStatement{
+		Windows: map[string]ast.WindowValue{
+			"column_1": {
+				Function: &ast.WindowFunctionCall{
+					Name:      "sum",
+					Arguments: []ast.Expression{&ast.SymbolExpression{Value: "value"}},
+					Window: ast.Window{
+						PartitionBy: []ast.Expression{&ast.SymbolExpression{Value: "name"}},
+						OrderBy: &ast.OrderByStatement{
+							Arguments:     []ast.Expression{&ast.SymbolExpression{Value: "value"}},
+							SortingOrders: []ast.SortingOrder{ast.Ascending},
+						},
+					},
+				},
+			},
+			"column_2": {
+				Expression: &ast.ArithmeticExpression{
+					Left:     &ast.SymbolExpression{Value: "column_1"},
+					Operator: ast.AOPlus,
+					Right:    &ast.NumberExpression{Value: ast.IntegerValue{Value: 1}},
+				},
+			},
+		},
+	}
+
+	object := ast.GitQLObject{
+		Titles: []string{"name", "value", "running", "column_2"},
+		Groups: []ast.Group{{Rows: newWindowRows()}},
+	}
+
+	err := executeWindowFunctionsStatement(&env, statement, &object, map[string]string{"column_1": "running"})
+	assert.Nil(t, err)
+
+	var running []int64
+	var expressions []int64
+	for _, row := range object.Groups[0].Rows {
+		running = append(running, row.Values[2].AsInt())
+		expressions = append(expressions, int64(row.Values[3].AsFloat()))
+	}
+	assert.Equal(t, []int64{5, 1, 2, 2}, running)
+	assert.Equal(t, []int64{6, 2, 3, 3}, expressions)
+
+	// Unknown window column
+	object = ast.GitQLObject{
+		Titles: []string{"name", "value"},
+		Groups: []ast.Group{{Rows: newWindowRows()}},
+	}
+
+	err = executeWindowFunctionsStatement(&env, statement, &object, map[string]string{})
+	assert.NotNil(t, err)
+}
+
+func TestWindowTitles(t *testing.T) {
+	titles := windowTitles([]string{"name", "n"}, map[string]string{"column_1": "n"})
+	assert.Equal(t, []string{"name", "column_1"}, titles)
+}
+
+func TestExecuteWindowFunction(t *testing.T) {
+	env := newMutationEnv()
+	rows := newWindowRows()
+
+	function := &ast.WindowFunctionCall{
+		Name: "row_number",
+		Window: ast.Window{
+			OrderBy: &ast.OrderByStatement{
+				Arguments:     []ast.Expression{&ast.SymbolExpression{Value: "value"}},
+				SortingOrders: []ast.SortingOrder{ast.Descending},
+			},
+		},
+	}
+
+	err := executeWindowFunction(&env, function, []string{"name", "value", "n"}, rows, 2)
+	assert.Nil(t, err)
+
+	var numbers []int64
+	for _, row := range rows {
+		numbers = append(numbers, row.Values[2].AsInt())
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4}, numbers)
+}
+
+func TestWindowPartitions(t *testing.T) {
+	env := newMutationEnv()
+	titles := []string{"name", "value"}
+
+	window := ast.Window{
+		PartitionBy: []ast.Expression{&ast.SymbolExpression{Value: "name"}},
+		OrderBy: &ast.OrderByStatement{
+			Arguments:     []ast.Expression{&ast.SymbolExpression{Value: "value"}},
+			SortingOrders: []ast.SortingOrder{ast.Ascending},
+		},
+	}
+
+	partitions, err := windowPartitions(&env, window, titles, newWindowRows())
+	assert.Nil(t, err)
+	assert.Equal(t, []windowRows{
+		{indexes: []int{2, 3, 0}, peersStart: []int{0, 0, 2}, peersEnd: []int{1, 1, 2}},
+		{indexes: []int{1}, peersStart: []int{0}, peersEnd: []int{0}},
+	}, partitions)
+
+	// Without `ORDER BY` all the partition rows are peers
+	partitions, err = windowPartitions(&env, ast.Window{}, titles, newWindowRows())
+	assert.Nil(t, err)
+	assert.Equal(t, []windowRows{
+		{indexes: []int{0, 1, 2, 3}, peersStart: []int{0, 0, 0, 0}, peersEnd: []int{3, 3, 3, 3}},
+	}, partitions)
+}
+
+func TestCompareOrderValues(t *testing.T) {
+	first := []ast.Value{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 1}}
+	other := []ast.Value{ast.TextValue{Value: "a"}, ast.IntegerValue{Value: 2}}
+
+	assert.Equal(t, -1, compareOrderValues(first, other, []ast.SortingOrder{ast.Ascending, ast.Ascending}))
+	assert.Equal(t, 1, compareOrderValues(first, other, []ast.SortingOrder{ast.Ascending, ast.Descending}))
+	assert.Equal(t, 0, compareOrderValues(first, first, []ast.SortingOrder{ast.Ascending, ast.Descending}))
+}
@@ -31,7 +31,19 @@ func main() {
 
 		repos := gitReposResult.ok
 		env := ast.Environment{}
-		executeGitqlQuery(command.QueryMode.Query, command.QueryMode.Arguments, repos, &env, &reporter)
+		executeGitqlQuery(command.QueryMode.Query, command.QueryMode.Arguments, false, repos, &env, &reporter)
+	}
+	if command.MutateMode.Mutate != "" {
+		reporter := cli.DiagnosticReporter{}
+		gitReposResult := validateGitRepositories(command.MutateMode.Arguments.Repos)
+		if gitReposResult.err != nil {
+			reporter.ReportDiagnostic("", &parser.Diagnostic{Message: gitReposResult.err.Error()})
+			return
+		}
+
+		repos := gitReposResult.ok
+		env := ast.Environment{}
+		executeGitqlQuery(command.MutateMode.Mutate, command.MutateMode.Arguments, true, repos, &env, &reporter)
 	}
 	if command.Help {
 		cli.PrintHelpList()
@@ -70,7 +82,7 @@ func launchGitqlRepl(args cli.Arguments) {
 			break
 		}
 
-		executeGitqlQuery(input, args, gitRepositories, &globalEnv, &reporter)
+		executeGitqlQuery(input, args, false, gitRepositories, &globalEnv, &reporter)
 		globalEnv.ClearSession()
 	}
 }
@@ -79,6 +91,7 @@ func launchGitqlRepl(args cli.Arguments) {
 func executeGitqlQuery(
 	query string,
 	args cli.Arguments,
+	mutate bool,
 	repos []*git.Repository,
 	env *ast.Environment,
 	reporter *cli.DiagnosticReporter,
@@ -100,14 +113,43 @@ func executeGitqlQuery(
 		return
 	}
 
+	if queryNode.IsMutation() && !mutate {
+		reporter.ReportDiagnostic(query, parser.NewError("Mutate statements can't be used in query mode").AddHelp("Try to run `INSERT`, `UPDATE` and `DELETE` statements with `--mutate` option"))
+		return
+	}
+
+	if !queryNode.IsMutation() && mutate {
+		reporter.ReportDiagnostic(query, parser.NewError("Expect `INSERT`, `UPDATE` or `DELETE` statement in mutate mode").AddHelp("Try to run queries with `--query` option"))
+		return
+	}
+
 	frontDuration := time.Since(frontStart)
 
 	engineStart := time.Now()
-	evaluationResult, err2 := engine.Evaluate(env, repos, queryNode)
+	var evaluationResult engine.EvaluationResult
+	var err2 error
+	if queryNode.IsMutation() {
+		options := engine.MutationOptions{
+			DryRun:      args.DryRun,
+			TaggerName:  args.TaggerName,
+			TaggerEmail: args.TaggerEmail,
+		}
+		evaluationResult, err2 = engine.EvaluateMutateQuery(env, repos, queryNode, options)
+	} else {
+		evaluationResult, err2 = engine.Evaluate(env, repos, queryNode)
+	}
 	if err2 != nil {
 		reporter.ReportDiagnostic(query, &parser.Diagnostic{Message: err2.Error()})
 		return
 	}
+	if queryNode.IsMutation() {
+		if args.DryRun {
+			fmt.Printf("Dry run, %d rows would be affected\n", evaluationResult.AffectedRows)
+		} else {
+			fmt.Printf("Query OK, %d rows affected\n", evaluationResult.AffectedRows)
+		}
+	}
+
 	if evaluationResult.SelectedGroups.Obj.Len() != 0 {
 		groups := evaluationResult.SelectedGroups.Obj
 		hiddenselection := evaluationResult.SelectedGroups.Str
@@ -132,7 +174,8 @@ func executeGitqlQuery(
 			for _, index := range indexes {
 				groups.Titles = append(groups.Titles[:index], groups.Titles[index+1:]...)
 
-				for _, row := range groups.Groups[0].Rows {
+				for rowIndex := range groups.Groups[0].Rows {
+					row := &groups.Groups[0].Rows[rowIndex]
 					row.Values = append(row.Values[:index], row.Values[index+1:]...)
 				}
 			}
@@ -155,7 +198,8 @@ func executeGitqlQuery(
 			for _, index := range indexes {
 				groups.Titles = append(groups.Titles[:index], groups.Titles[index+1:]...)
 
-				for _, row := range groups.Groups[0].Rows {
+				for rowIndex := range groups.Groups[0].Rows {
+					row := &groups.Groups[0].Rows[rowIndex]
 					row.Values = append(row.Values[:index], row.Values[index+1:]...)
 				}
 			}
@@ -8,25 +8,83 @@ import (
 
 type ParserContext struct {
 	Aggregations        map[string]ast.AggregateValue
+	Windows             map[string]ast.WindowValue
 	SelectedFields      []string
 	HiddenSelections    []string
 	GeneratedFieldCount int32
 	IsSingleValueQuery  bool
 	HasGroupByStatement bool
+	TablesNames         map[string]string
+	TablesReferences    []string
+	DerivedTables       map[string]*ast.DerivedTable
+	CommonTables        map[string]*ast.DerivedTable
+	Subqueries          []*ast.SubqueryExpression
+	IsSetOperand        bool
 }
 
 func NewParserContext() *ParserContext {
 	return &ParserContext{
 		Aggregations:        make(map[string]ast.AggregateValue),
+		Windows:             make(map[string]ast.WindowValue),
 		SelectedFields:      make([]string, 0),
 		HiddenSelections:    make([]string, 0),
 		GeneratedFieldCount: 0,
 		IsSingleValueQuery:  false,
 		HasGroupByStatement: false,
+		TablesNames:         make(map[string]string),
+		TablesReferences:    make([]string, 0),
+		DerivedTables:       make(map[string]*ast.DerivedTable),
+		CommonTables:        make(map[string]*ast.DerivedTable),
+		Subqueries:          make([]*ast.SubqueryExpression, 0),
 	}
 }
 
+// NewSubqueryContext returns the context of a subquery which can use the common tables visible to the outer query
+func (p *ParserContext) NewSubqueryContext() *ParserContext {
+	context := NewParserContext()
+	for name, table := range p.CommonTables {
+		context.CommonTables[name] = table
+	}
+	return context
+}
+
 func (p *ParserContext) GenerateColumnName() string {
 	p.GeneratedFieldCount++
 	return fmt.Sprintf("column_%d", p.GeneratedFieldCount)
 }
+
+// RegisterTable makes the table fields resolvable by the table name or its alias
+func (p *ParserContext) RegisterTable(reference, tableName string) {
+	if p.TablesNames == nil {
+		p.TablesNames = make(map[string]string)
+	}
+	p.TablesNames[reference] = tableName
+	p.TablesReferences = append(p.TablesReferences, reference)
+}
+
+func (p *ParserContext) IsJoinQuery() bool {
+	return len(p.TablesReferences) > 1
+}
+
+// RegisterDerivedTable makes the fields selected by the derived table query resolvable by the table alias
+func (p *ParserContext) RegisterDerivedTable(alias string, table *ast.DerivedTable) {
+	if p.DerivedTables == nil {
+		p.DerivedTables = make(map[string]*ast.DerivedTable)
+	}
+	p.DerivedTables[alias] = table
+	p.RegisterTable(alias, alias)
+}
+
+func (p *ParserContext) TableFieldsNames(reference string) []string {
+	if table, ok := p.DerivedTables[reference]; ok {
+		return table.FieldsNames
+	}
+	return ast.TablesFieldsNames[p.TablesNames[reference]]
+}
+
+func (p *ParserContext) TableFieldType(reference, fieldName string) ast.DataType {
+	if table, ok := p.DerivedTables[reference]; ok {
+		return table.FieldsTypes[fieldName]
+	}
+	return ast.TableFieldType(p.TablesNames[reference], fieldName)
+}
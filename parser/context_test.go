@@ -3,6 +3,8 @@ package parser
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/ggql/ggql/ast"
 )
 
@@ -24,3 +26,44 @@ func TestGenerateColumnName(t *testing.T) {
 		t.Error("Generated column name should be 'column_2'")
 	}
 }
+
+func TestRegisterTable(t *testing.T) {
+	parserContext := &ParserContext{}
+
+	parserContext.RegisterTable("commits", "commits")
+	assert.Equal(t, false, parserContext.IsJoinQuery())
+
+	parserContext.RegisterTable("d", "diffs")
+	assert.Equal(t, true, parserContext.IsJoinQuery())
+	assert.Equal(t, "diffs", parserContext.TablesNames["d"])
+	assert.Equal(t, []string{"commits", "d"}, parserContext.TablesReferences)
+}
+
+func TestRegisterDerivedTable(t *testing.T) {
+	parserContext := NewParserContext()
+	parserContext.RegisterTable("c", "commits")
+	parserContext.RegisterDerivedTable("d", &ast.DerivedTable{
+		FieldsNames: []string{"id"},
+		FieldsTypes: map[string]ast.DataType{"id": ast.Integer{}},
+	})
+
+	assert.Equal(t, []string{"c", "d"}, parserContext.TablesReferences)
+	assert.Equal(t, []string{"id"}, parserContext.TableFieldsNames("d"))
+	assert.Equal(t, ast.TablesFieldsNames["commits"], parserContext.TableFieldsNames("c"))
+	assert.Equal(t, ast.Integer{}, parserContext.TableFieldType("d", "id"))
+	assert.Equal(t, ast.Text{}, parserContext.TableFieldType("c", "title"))
+}
+
+func TestNewSubqueryContext(t *testing.T) {
+	parserContext := NewParserContext()
+	parserContext.RegisterTable("c", "commits")
+	parserContext.CommonTables["b"] = &ast.DerivedTable{IsCommon: true}
+
+	subqueryContext := parserContext.NewSubqueryContext()
+	assert.Equal(t, parserContext.CommonTables["b"], subqueryContext.CommonTables["b"])
+	assert.Equal(t, 0, len(subqueryContext.TablesReferences))
+
+	// Common tables defined in the subquery are not visible to the outer query
+	subqueryContext.CommonTables["t"] = &ast.DerivedTable{IsCommon: true}
+	assert.Nil(t, parserContext.CommonTables["t"])
+}
@@ -3,12 +3,16 @@ package parser
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/ggql/ggql/ast"
 )
 
+// setOperationsStatements are the statements of the last set operation query applied on the combined rows
+var setOperationsStatements = []string{"order", "offset", "limit"}
+
 func ParserGql(tokens []Token, env *ast.Environment) (ast.Query, Diagnostic) {
 	position := 0
 	firstToken := tokens[position]
@@ -17,12 +21,24 @@ func ParserGql(tokens []Token, env *ast.Environment) (ast.Query, Diagnostic) {
 	switch firstToken.Kind {
 	case Set:
 		queryResult, err = ParseSetQuery(env, &tokens, &position)
+	case With:
+		queryResult, err = ParseWithQuery(env, &tokens, &position)
 	case Select:
 		queryResult, err = ParseSelectQuery(env, &tokens, &position)
+	case Insert:
+		queryResult, err = ParseInsertQuery(env, &tokens, &position)
+	case Update:
+		queryResult, err = ParseUpdateQuery(env, &tokens, &position)
+	case Delete:
+		queryResult, err = ParseDeleteQuery(env, &tokens, &position)
 	default:
 		err = UnExpectedStatementError(&tokens, &position)
 	}
 
+	if err.Message != "" {
+		return queryResult, err
+	}
+
 	if position < len(tokens) {
 		lastToken := tokens[position]
 		if lastToken.Kind == Semicolon {
@@ -86,10 +102,413 @@ func ParseSetQuery(env *ast.Environment, tokens *[]Token, position *int) (ast.Qu
 }
 
 // nolint:funlen,gocyclo,lll
+func ParseInsertQuery(env *ast.Environment, tokens *[]Token, position *int) (ast.Query, Diagnostic) {
+	lentokens := len(*tokens)
+	context := NewParserContext()
+
+	// Consume `INSERT` keyword
+	*position += 1
+
+	if *position >= lentokens || (*tokens)[*position].Kind != Into {
+		return ast.Query{}, *NewError("Expect `INTO` keyword after `INSERT` keyword").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Consume `INTO` keyword
+	*position += 1
+
+	tableName, err := ParseMutableTableName(tokens, position)
+	if err.Message != "" {
+		return ast.Query{}, err
+	}
+
+	if *position >= lentokens || (*tokens)[*position].Kind != LeftParen {
+		return ast.Query{}, *NewError("Expect `(` and fields names after table name").AddHelp("Try to add the fields names between `(` and `)` after the table name").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Consume `(` token
+	*position += 1
+
+	var fieldsNames []string
+	for *position < lentokens && (*tokens)[*position].Kind != RightParen {
+		fieldNameToken, err := ConsumeKind(*tokens, *position, Symbol)
+		if err != nil {
+			return ast.Query{}, *NewError("Expect `identifier` as a field name").WithLocation(GetSafeLocation(tokens, *position))
+		}
+
+		fieldName := fieldNameToken.Literal
+		if !contains(ast.TablesMutableFieldsNames[tableName], fieldName) {
+			return ast.Query{}, *NewError(fmt.Sprintf("Table `%s` has no mutable field with name `%s`", tableName, fieldName)).AddNote(fmt.Sprintf("Mutable fields are %s", strings.Join(ast.TablesMutableFieldsNames[tableName], ", "))).WithLocation(GetSafeLocation(tokens, *position))
+		}
+
+		if contains(fieldsNames, fieldName) {
+			return ast.Query{}, *NewError("Can't insert the same field twice").WithLocation(GetSafeLocation(tokens, *position))
+		}
+
+		fieldsNames = append(fieldsNames, fieldName)
+
+		// Consume field name
+		*position += 1
+
+		// Consume `,` or break
+		if *position < lentokens && (*tokens)[*position].Kind == Comma {
+			*position += 1
+		} else {
+			break
+		}
+	}
+
+	if *position >= lentokens || (*tokens)[*position].Kind != RightParen {
+		return ast.Query{}, *NewError("Expect `)` after fields names").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume `)` token
+	*position += 1
+
+	if len(fieldsNames) == 0 {
+		return ast.Query{}, *NewError("Insert statement requires at least one field name").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Insert the rows selected by a query
+	if *position < lentokens && (*tokens)[*position].Kind == Select {
+		location := GetSafeLocation(tokens, *position)
+
+		selectQuery, err := ParseSelectQuery(env, tokens, position)
+		if err.Message != "" {
+			return ast.Query{}, err
+		}
+
+		err = TypeCheckInsertSelect(env, tableName, fieldsNames, selectQuery.Select, location)
+		if err.Message != "" {
+			return ast.Query{}, err
+		}
+
+		return ast.Query{
+			Insert: &ast.InsertStatement{
+				TableName:   tableName,
+				FieldsNames: fieldsNames,
+				Select:      selectQuery.Select,
+			},
+		}, Diagnostic{}
+	}
+
+	if *position >= lentokens || (*tokens)[*position].Kind != Values {
+		return ast.Query{}, *NewError("Expect `VALUES` keyword or `SELECT` statement after fields names").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Consume `VALUES` keyword
+	*position += 1
+
+	var values [][]ast.Expression
+	for {
+		row, err := ParseInsertValues(context, env, tableName, fieldsNames, tokens, position)
+		if err.Message != "" {
+			return ast.Query{}, err
+		}
+
+		values = append(values, row)
+
+		// Consume `,` or break
+		if *position < lentokens && (*tokens)[*position].Kind == Comma {
+			*position += 1
+		} else {
+			break
+		}
+	}
+
+	return ast.Query{
+		Insert: &ast.InsertStatement{
+			TableName:   tableName,
+			FieldsNames: fieldsNames,
+			Values:      values,
+		},
+		Subqueries: context.Subqueries,
+	}, Diagnostic{}
+}
+
+// nolint:lll
+func ParseInsertValues(context *ParserContext, env *ast.Environment, tableName string, fieldsNames []string, tokens *[]Token, position *int) ([]ast.Expression, Diagnostic) {
+	lentokens := len(*tokens)
+	location := GetSafeLocation(tokens, *position)
+
+	if *position >= lentokens || (*tokens)[*position].Kind != LeftParen {
+		return nil, *NewError("Expect values between `(` and `)` after `VALUES` keyword").WithLocation(location)
+	}
+
+	// Consume `(` token
+	*position += 1
+
+	var values []ast.Expression
+	for *position < lentokens && (*tokens)[*position].Kind != RightParen {
+		value, err := ParseExpression(context, env, tokens, position)
+		if err.Message != "" {
+			return nil, err
+		}
+
+		values = append(values, value)
+
+		// Consume `,` or break
+		if *position < lentokens && (*tokens)[*position].Kind == Comma {
+			*position += 1
+		} else {
+			break
+		}
+	}
+
+	if *position >= lentokens || (*tokens)[*position].Kind != RightParen {
+		return nil, *NewError("Expect `)` after insert values").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume `)` token
+	*position += 1
+
+	if len(values) != len(fieldsNames) {
+		return nil, *NewError(fmt.Sprintf("Expect `%d` values but got `%d`", len(fieldsNames), len(values))).AddNote("Each values row must have one value for each field name").WithLocation(location)
+	}
+
+	for index := range values {
+		err := TypeCheckMutatedField(env, tableName, fieldsNames[index], &values[index], location)
+		if err.Message != "" {
+			return nil, err
+		}
+	}
+
+	return values, Diagnostic{}
+}
+
+// nolint:funlen,lll
+func ParseUpdateQuery(env *ast.Environment, tokens *[]Token, position *int) (ast.Query, Diagnostic) {
+	lentokens := len(*tokens)
+	context := NewParserContext()
+
+	// Consume `UPDATE` keyword
+	*position += 1
+
+	tableName, err := ParseMutableTableName(tokens, position)
+	if err.Message != "" {
+		return ast.Query{}, err
+	}
+
+	context.RegisterTable(tableName, tableName)
+	RegisterCurrentTableFieldsTypes(tableName, env)
+
+	if *position >= lentokens || (*tokens)[*position].Kind != Set {
+		return ast.Query{}, *NewError("Expect `SET` keyword after table name").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Consume `SET` keyword
+	*position += 1
+
+	var fieldsNames []string
+	var fieldsValues []ast.Expression
+	for {
+		fieldNameToken, err := ConsumeKind(*tokens, *position, Symbol)
+		if err != nil {
+			return ast.Query{}, *NewError("Expect `identifier` as a field name").WithLocation(GetSafeLocation(tokens, *position))
+		}
+
+		fieldName := fieldNameToken.Literal
+		if !contains(ast.TablesMutableFieldsNames[tableName], fieldName) {
+			return ast.Query{}, *NewError(fmt.Sprintf("Table `%s` has no mutable field with name `%s`", tableName, fieldName)).AddNote(fmt.Sprintf("Mutable fields are %s", strings.Join(ast.TablesMutableFieldsNames[tableName], ", "))).WithLocation(GetSafeLocation(tokens, *position))
+		}
+
+		if contains(fieldsNames, fieldName) {
+			return ast.Query{}, *NewError("Can't update the same field twice").WithLocation(GetSafeLocation(tokens, *position))
+		}
+
+		// Consume field name
+		*position += 1
+
+		if *position >= lentokens || (*tokens)[*position].Kind != Equal {
+			return ast.Query{}, *NewError("Expect `=` and value after field name").WithLocation(GetSafeLocation(tokens, *position-1))
+		}
+
+		// Consume `=` token
+		*position += 1
+
+		location := GetSafeLocation(tokens, *position)
+		value, errValue := ParseExpression(context, env, tokens, position)
+		if errValue.Message != "" {
+			return ast.Query{}, errValue
+		}
+
+		errType := TypeCheckMutatedField(env, tableName, fieldName, &value, location)
+		if errType.Message != "" {
+			return ast.Query{}, errType
+		}
+
+		fieldsNames = append(fieldsNames, fieldName)
+		fieldsValues = append(fieldsValues, value)
+
+		// Consume `,` or break
+		if *position < lentokens && (*tokens)[*position].Kind == Comma {
+			*position += 1
+		} else {
+			break
+		}
+	}
+
+	if len(context.Aggregations) != 0 {
+		return ast.Query{}, *NewError("Can't use Aggregation functions in `UPDATE` statement").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	statement := &ast.UpdateStatement{
+		TableName:    tableName,
+		FieldsNames:  fieldsNames,
+		FieldsValues: fieldsValues,
+	}
+
+	if *position < lentokens && (*tokens)[*position].Kind == Where {
+		where, err := ParseWhereStatement(context, env, tokens, position)
+		if err.Message != "" {
+			return ast.Query{}, err
+		}
+		statement.Where = where.(*ast.WhereStatement)
+	}
+
+	return ast.Query{Update: statement, Subqueries: context.Subqueries}, Diagnostic{}
+}
+
+// nolint:lll
+func ParseDeleteQuery(env *ast.Environment, tokens *[]Token, position *int) (ast.Query, Diagnostic) {
+	lentokens := len(*tokens)
+	context := NewParserContext()
+
+	// Consume `DELETE` keyword
+	*position += 1
+
+	if *position >= lentokens || (*tokens)[*position].Kind != From {
+		return ast.Query{}, *NewError("Expect `FROM` keyword after `DELETE` keyword").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Consume `FROM` keyword
+	*position += 1
+
+	tableName, err := ParseMutableTableName(tokens, position)
+	if err.Message != "" {
+		return ast.Query{}, err
+	}
+
+	context.RegisterTable(tableName, tableName)
+	RegisterCurrentTableFieldsTypes(tableName, env)
+
+	statement := &ast.DeleteStatement{
+		TableName: tableName,
+	}
+
+	if *position < lentokens && (*tokens)[*position].Kind == Where {
+		where, err := ParseWhereStatement(context, env, tokens, position)
+		if err.Message != "" {
+			return ast.Query{}, err
+		}
+		statement.Where = where.(*ast.WhereStatement)
+	}
+
+	return ast.Query{Delete: statement, Subqueries: context.Subqueries}, Diagnostic{}
+}
+
+// nolint:lll
+func ParseMutableTableName(tokens *[]Token, position *int) (string, Diagnostic) {
+	tableNameToken, err := ConsumeKind(*tokens, *position, Symbol)
+	if err != nil {
+		return "", *NewError("Expect `identifier` as a table name").AddNote("Table name must be an identifier").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	tableName := tableNameToken.Literal
+	if _, ok := ast.TablesMutableFieldsNames[tableName]; !ok {
+		mutableTables := make([]string, 0, len(ast.TablesMutableFieldsNames))
+		for name := range ast.TablesMutableFieldsNames {
+			mutableTables = append(mutableTables, name)
+		}
+		sort.Strings(mutableTables)
+
+		return "", *NewError(fmt.Sprintf("Table `%s` can't be mutated", tableName)).AddHelp(fmt.Sprintf("Mutable tables are %s", strings.Join(mutableTables, ", "))).WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume table name
+	*position += 1
+
+	return tableName, Diagnostic{}
+}
+
+// nolint:lll
+func ParseWithQuery(env *ast.Environment, tokens *[]Token, position *int) (ast.Query, Diagnostic) {
+	context := NewParserContext()
+
+	// Consume `WITH` keyword
+	*position += 1
+
+	for {
+		name, table, err := ParseCommonTableExpression(context, env, tokens, position)
+		if err.Message != "" {
+			return ast.Query{}, err
+		}
+
+		// Common tables are visible to the next common table expressions and to the query
+		context.CommonTables[name] = table
+
+		if *position < len(*tokens) && (*tokens)[*position].Kind == Comma {
+			// Consume `,`
+			*position += 1
+			continue
+		}
+		break
+	}
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != Select {
+		return ast.Query{}, *NewError("Expect `SELECT` query after common table expressions").AddHelp("Try to use the common tables in a query like `WITH t AS (SELECT ...) SELECT * FROM t`").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	return ParseSelectQueryWithContext(context, env, tokens, position)
+}
+
+// ParseCommonTableExpression parses a named query like `name AS (SELECT ...)`
+// nolint:lll
+func ParseCommonTableExpression(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (string, *ast.DerivedTable, Diagnostic) {
+	nameToken, consumeErr := ConsumeKind(*tokens, *position, Symbol)
+	if consumeErr != nil {
+		return "", nil, *NewError("Expect `identifier` as common table expression name").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	name := nameToken.Literal
+	if _, ok := context.CommonTables[name]; ok {
+		return "", nil, *NewError(fmt.Sprintf("Common table expression `%s` is defined more than once", name)).AddHelp("Try to use a new unique name for the common table expression").WithLocation(nameToken.Location)
+	}
+
+	// Consume common table name
+	*position += 1
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != As {
+		return "", nil, *NewError("Expect `AS` keyword after common table expression name").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume `AS` keyword
+	*position += 1
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != LeftParen {
+		return "", nil, *NewError("Expect `(` before common table expression query").AddHelp("Try to add the query between `(` and `)`").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	query, scope, err := ParseSubquery(context, env, tokens, position)
+	if err.Message != "" {
+		return "", nil, err
+	}
+
+	table := NewDerivedTable(query, scope)
+	table.IsCommon = true
+
+	return name, table, Diagnostic{}
+}
+
+// nolint:lll
 func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast.Query, Diagnostic) {
+	return ParseSelectQueryWithContext(NewParserContext(), env, tokens, position)
+}
+
+// nolint:funlen,gocyclo,lll
+func ParseSelectQueryWithContext(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (ast.Query, Diagnostic) {
 	lentokens := len(*tokens)
-	context := ParserContext{}
 	statements := make(map[string]ast.Statement)
+	var setOperations []ast.SetOperation
 
 	for *position < lentokens {
 		token := &(*tokens)[*position]
@@ -98,7 +517,7 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 			if _, ok := statements["select"]; ok {
 				return ast.Query{}, *NewError("You already used `SELECT` statement").AddNote("Can't use more than one `SELECT` statement in the same query").WithLocation(token.Location)
 			}
-			statement, err := ParseSelectStatement(&context, env, tokens, position)
+			statement, err := ParseSelectStatement(context, env, tokens, position)
 			if err.Message != "" {
 				return ast.Query{}, err
 			}
@@ -108,7 +527,7 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 			if _, ok := statements["where"]; ok {
 				return ast.Query{}, *NewError("You already used `WHERE` statement").AddNote("Can't use more than one `WHERE` statement in the same query").WithLocation(token.Location)
 			}
-			statement, err := ParseWhereStatement(&context, env, tokens, position)
+			statement, err := ParseWhereStatement(context, env, tokens, position)
 			if err.Message != "" {
 				return ast.Query{}, err
 			}
@@ -117,7 +536,7 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 			if _, ok := statements["group"]; ok {
 				return ast.Query{}, *NewError("You already used `GROUP BY` statement").AddNote("Can't use more than one `GROUP BY` statement in the same query").WithLocation(token.Location)
 			}
-			statement, err := ParseGroupByStatement(&context, env, tokens, position)
+			statement, err := ParseGroupByStatement(context, env, tokens, position)
 			if err.Message != "" {
 				return ast.Query{}, err
 			}
@@ -129,7 +548,7 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 			if _, ok := statements["group"]; !ok {
 				return ast.Query{}, *NewError("`HAVING` must be used after `GROUP BY` statement").AddNote("`HAVING` statement must be used in a query that has `GROUP BY` statement").WithLocation(token.Location)
 			}
-			statement, err := ParseHavingStatement(&context, env, tokens, position)
+			statement, err := ParseHavingStatement(context, env, tokens, position)
 			if err.Message != "" {
 				return ast.Query{}, err
 			}
@@ -166,14 +585,54 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 			if _, ok := statements["order"]; ok {
 				return ast.Query{}, *NewError("You already used `ORDER BY` statement").AddHelp("Can't use more than one `ORDER BY` statement in the same query").WithLocation(token.Location)
 			}
-			statement, _ := ParseOrderByStatement(&context, env, tokens, position)
+			statement, _ := ParseOrderByStatement(context, env, tokens, position)
 			statements["order"] = statement
+		case RightParen:
+			// End of subquery, the caller consumes the `)`
+			lentokens = *position
+		case Union, Intersect, Except:
+			for _, statementName := range setOperationsStatements {
+				if _, ok := statements[statementName]; ok {
+					return ast.Query{}, *NewError("`ORDER BY`, `LIMIT` and `OFFSET` must be used after the last query of set operations").AddNote("They are applied to the combined rows of the queries").WithLocation(token.Location)
+				}
+			}
+			if context.IsSetOperand {
+				// End of set operation query, the first query parses the next set operation
+				lentokens = *position
+				break
+			}
+			selectStatement, ok := statements["select"].(*ast.SelectStatement)
+			if !ok {
+				return ast.Query{}, *NewError(fmt.Sprintf("Expect `SELECT` statement before `%s`", strings.ToUpper(token.Literal))).WithLocation(token.Location)
+			}
+			operation, err := ParseSetOperation(context, env, selectStatement, tokens, position)
+			if err.Message != "" {
+				return ast.Query{}, err
+			}
+			setOperations = append(setOperations, operation)
 		default:
 			*position += 1
 			break
 		}
 	}
 
+	// `ORDER BY`, `OFFSET` and `LIMIT` after the last query of set operations are applied on the combined rows
+	if len(setOperations) != 0 {
+		lastQuery := setOperations[len(setOperations)-1].Query
+		for _, statementName := range setOperationsStatements {
+			if statement, ok := lastQuery.Statements[statementName]; ok {
+				statements[statementName] = statement
+				delete(lastQuery.Statements, statementName)
+			}
+		}
+	}
+
+	if selectStatement, ok := statements["select"].(*ast.SelectStatement); ok {
+		if whereStatement, ok := statements["where"].(*ast.WhereStatement); ok {
+			selectStatement.Condition = whereStatement.Condition
+		}
+	}
+
 	// If any aggregation function is used, add Aggregation Functions Node to the GQL Query
 	if len(context.Aggregations) != 0 {
 		aggregationFunctions := &ast.AggregationsStatement{
@@ -182,6 +641,13 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 		statements["aggregation"] = aggregationFunctions
 	}
 
+	// If any window function is used, add Window Functions Node to the GQL Query
+	if len(context.Windows) != 0 {
+		statements["window"] = &ast.WindowFunctionsStatement{
+			Windows: context.Windows,
+		}
+	}
+
 	// Remove all selected fields from hidden selection
 	hiddenSelections := make([]string, 0)
 	for _, selection := range context.HiddenSelections {
@@ -196,10 +662,88 @@ func ParseSelectQuery(env *ast.Environment, tokens *[]Token, position *int) (ast
 			HasAggregationFunction: context.IsSingleValueQuery,
 			HasGroupByStatement:    context.HasGroupByStatement,
 			HiddenSelections:       hiddenSelections,
+			Subqueries:             context.Subqueries,
+			SetOperations:          setOperations,
 		},
 	}, Diagnostic{}
 }
 
+// ParseSetOperation parses `UNION`, `INTERSECT` or `EXCEPT` with optional `ALL` and the select query after it, the
+// query must select the same number of values with the same types as the first query
+// nolint:lll
+func ParseSetOperation(context *ParserContext, env *ast.Environment, selectStatement *ast.SelectStatement, tokens *[]Token, position *int) (ast.SetOperation, Diagnostic) {
+	operatorToken := (*tokens)[*position]
+	operatorName := strings.ToUpper(operatorToken.Literal)
+
+	var operator ast.SetOperator
+	switch operatorToken.Kind {
+	case Union:
+		operator = ast.SOUnion
+	case Intersect:
+		operator = ast.SOIntersect
+	default:
+		operator = ast.SOExcept
+	}
+
+	// Consume set operator
+	*position += 1
+
+	isAll := *position < len(*tokens) && (*tokens)[*position].Kind == All
+	if isAll {
+		// Consume `ALL` keyword
+		*position += 1
+	}
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != Select {
+		return ast.SetOperation{}, *NewError(fmt.Sprintf("Expect `SELECT` query after `%s`", operatorName)).WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	queryLocation := (*tokens)[*position].Location
+
+	scope := env.NewScope()
+	operandContext := context.NewSubqueryContext()
+	operandContext.IsSetOperand = true
+
+	query, err := ParseSelectQueryWithContext(operandContext, scope, tokens, position)
+	if err.Message != "" {
+		return ast.SetOperation{}, err
+	}
+
+	firstTypes := SelectedFieldsTypes(selectStatement, env)
+	otherTypes := SelectedFieldsTypes(query.Select.Statements["select"].(*ast.SelectStatement), scope)
+	if len(firstTypes) != len(otherTypes) {
+		return ast.SetOperation{}, *NewError(fmt.Sprintf("`%s` queries must select the same number of values but got `%d` and `%d`", operatorName, len(firstTypes), len(otherTypes))).WithLocation(queryLocation)
+	}
+
+	for index, firstType := range firstTypes {
+		otherType := otherTypes[index]
+		if firstType.IsAny() || otherType.IsAny() || firstType.Equal(otherType) {
+			continue
+		}
+		return ast.SetOperation{}, *NewError(fmt.Sprintf("`%s` queries must select the same types but value `%d` is %s and %s", operatorName, index+1, firstType.Fmt(), otherType.Fmt())).AddHelp("Try to select values with the same types in the same order").WithLocation(queryLocation)
+	}
+
+	return ast.SetOperation{
+		Operator: operator,
+		IsAll:    isAll,
+		Query:    query.Select,
+		Env:      scope,
+	}, Diagnostic{}
+}
+
+// SelectedFieldsTypes returns the types of the values selected by the statement in order
+func SelectedFieldsTypes(statement *ast.SelectStatement, scope *ast.Environment) []ast.DataType {
+	types := make([]ast.DataType, 0, len(statement.FieldsNames))
+	for _, fieldName := range statement.FieldsNames {
+		if aliasName, ok := statement.AliasTable[fieldName]; ok {
+			fieldName = aliasName
+		}
+		fieldType, _ := scope.ResolveType(fieldName)
+		types = append(types, fieldType)
+	}
+	return types
+}
+
 // nolint:funlen,gocyclo,lll
 func ParseSelectStatement(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (ast.Statement, Diagnostic) {
 	*position += 1
@@ -209,12 +753,25 @@ func ParseSelectStatement(context *ParserContext, env *ast.Environment, tokens *
 	}
 
 	var tableName string
+	var tableAlias string
+	var joins []ast.Join
 	var fieldsNames []string
 	var fieldsValues []ast.Expression
 	aliasTable := make(map[string]string)
 	isSelectAll := false
 	isDistinct := false
 
+	// The `FROM` statement is parsed first so the selected fields can be resolved against the tables names and aliases
+	fromPosition := FindFromKeyword(tokens, *position)
+	fromEnd := fromPosition
+	if fromPosition != -1 {
+		var err Diagnostic
+		tableName, tableAlias, joins, err = ParseFromStatement(context, env, tokens, &fromEnd)
+		if err.Message != "" {
+			return nil, err
+		}
+	}
+
 	// Check if select has distinct keyword after it
 	if (*tokens)[*position].Kind == Distinct {
 		isDistinct = true
@@ -288,61 +845,366 @@ func ParseSelectStatement(context *ParserContext, env *ast.Environment, tokens *
 		}
 	}
 
-	// Parse optional Form statement
-	if *position < len(*tokens) && (*tokens)[*position].Kind == From {
-		// Consume `from` keyword
+	// Parse optional Form statement
+	if fromPosition != -1 && *position == fromPosition {
+		*position = fromEnd
+	} else {
+		tableName = ""
+		joins = nil
+	}
+
+	// Make sure `SELECT *` used with specific table
+	if isSelectAll && tableName == "" {
+		return nil, *NewError("Expect `FROM` and table name after `SELECT *`").AddNote("Select all must be used with valid table name").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Select input validations
+	if !isSelectAll && len(fieldsNames) == 0 {
+		return nil, *NewError("Incomplete input for select statement").AddHelp("Try select one or more values in the `SELECT` statement").AddNote("Select statements requires at least selecting one value").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	tableReference := tableName
+	if tableAlias != "" {
+		tableReference = tableAlias
+	}
+	derivedTable := context.DerivedTables[tableReference]
+
+	// If it `select *` make all table fields selectable
+	if isSelectAll && (len(joins) != 0 || derivedTable != nil) {
+		SelectAllFromTablesFields(
+			context,
+			env,
+			&fieldsNames,
+			&fieldsValues,
+		)
+	} else if isSelectAll {
+		SelectAllTableFields(
+			tableName,
+			&context.SelectedFields,
+			&fieldsNames,
+			&fieldsValues,
+		)
+	}
+
+	// Type check all selected fields has type registered in type table
+	err := TypeCheckSelectedFields(env, tableName, &fieldsNames, tokens, *position)
+	if err.Message != "" {
+		return nil, err
+	}
+
+	return &ast.SelectStatement{
+		TableName:    tableName,
+		TableAlias:   tableAlias,
+		DerivedTable: derivedTable,
+		Joins:        joins,
+		FieldsNames:  fieldsNames,
+		FieldsValues: fieldsValues,
+		AliasTable:   aliasTable,
+		IsDistinct:   isDistinct,
+	}, Diagnostic{}
+}
+
+// FindFromKeyword returns the position of the `FROM` keyword of the current select statement or -1
+func FindFromKeyword(tokens *[]Token, position int) int {
+	depth := 0
+	for index := position; index < len(*tokens); index++ {
+		switch (*tokens)[index].Kind {
+		case LeftParen:
+			depth++
+		case RightParen:
+			depth--
+		case From:
+			if depth == 0 {
+				return index
+			}
+		case Where, Group, Having, Order, Limit, Offset, Union, Intersect, Except, Semicolon:
+			if depth == 0 {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// nolint:lll
+func ParseFromStatement(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (string, string, []ast.Join, Diagnostic) {
+	// Consume `from` keyword
+	*position += 1
+
+	tableName, tableAlias, err := ParseTableReference(context, env, tokens, position)
+	if err.Message != "" {
+		return "", "", nil, err
+	}
+
+	var joins []ast.Join
+	for *position < len(*tokens) && IsJoinKeyword(tokens, *position) {
+		join, err := ParseJoin(context, env, tokens, position)
+		if err.Message != "" {
+			return "", "", nil, err
+		}
+		joins = append(joins, join)
+	}
+
+	return tableName, tableAlias, joins, Diagnostic{}
+}
+
+// nolint:lll
+func ParseTableReference(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (string, string, Diagnostic) {
+	if *position < len(*tokens) && (*tokens)[*position].Kind == LeftParen {
+		return ParseDerivedTable(context, env, tokens, position)
+	}
+
+	tableNameToken, err := ConsumeKind(*tokens, *position, Symbol)
+	if err != nil {
+		return "", "", *NewError("Expect `identifier` as a table name").AddNote("Table name must be an identifier").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume table name
+	*position += 1
+
+	tableName := tableNameToken.Literal
+	commonTable := context.CommonTables[tableName]
+	if _, ok := ast.TablesFieldsNames[tableName]; !ok && commonTable == nil {
+		return "", "", *NewError("Unresolved table name").AddHelp("Check the documentations to see available tables").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Check for optional table alias, `LEFT` is reserved for `LEFT JOIN`
+	var tableAlias string
+	hasAsKeyword := *position < len(*tokens) && (*tokens)[*position].Kind == As
+	if hasAsKeyword {
+		// Consume `as` keyword
+		*position += 1
+	}
+	if *position < len(*tokens) && (*tokens)[*position].Kind == Symbol && (hasAsKeyword || !IsJoinKeyword(tokens, *position)) {
+		tableAlias = (*tokens)[*position].Literal
+		// Consume alias name
+		*position += 1
+	} else if hasAsKeyword {
+		return "", "", *NewError("Expect `identifier` as table alias name").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	reference := tableName
+	if tableAlias != "" {
+		reference = tableAlias
+	}
+	if _, ok := context.TablesNames[reference]; ok {
+		return "", "", *NewError(fmt.Sprintf("Table name or alias `%s` is used more than once", reference)).AddHelp("Try to use a new unique alias for the table").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	if commonTable != nil {
+		context.RegisterDerivedTable(reference, commonTable)
+		DefineDerivedTableFields(commonTable, env)
+		return tableName, tableAlias, Diagnostic{}
+	}
+
+	context.RegisterTable(reference, tableName)
+	RegisterCurrentTableFieldsTypes(tableName, env)
+
+	return tableName, tableAlias, Diagnostic{}
+}
+
+// nolint:lll
+func ParseDerivedTable(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (string, string, Diagnostic) {
+	query, scope, err := ParseSubquery(context, env, tokens, position)
+	if err.Message != "" {
+		return "", "", err
+	}
+
+	// Consume optional `as` keyword
+	if *position < len(*tokens) && (*tokens)[*position].Kind == As {
+		*position += 1
+	}
+
+	aliasToken, consumeErr := ConsumeKind(*tokens, *position, Symbol)
+	if consumeErr != nil {
+		return "", "", *NewError("Expect `identifier` as derived table alias name").AddHelp("Try to name the subquery result like `(SELECT ...) AS t`").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume alias name
+	*position += 1
+
+	alias := aliasToken.Literal
+	if _, ok := context.TablesNames[alias]; ok {
+		return "", "", *NewError(fmt.Sprintf("Table name or alias `%s` is used more than once", alias)).AddHelp("Try to use a new unique alias for the table").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	table := NewDerivedTable(query, scope)
+	context.RegisterDerivedTable(alias, table)
+	DefineDerivedTableFields(table, env)
+
+	return alias, alias, Diagnostic{}
+}
+
+// NewDerivedTable returns a table with the fields selected by the query, the fields are named by their aliases
+func NewDerivedTable(query *ast.GQLQuery, scope *ast.Environment) *ast.DerivedTable {
+	table := &ast.DerivedTable{
+		Query:       query,
+		Env:         scope,
+		FieldsTypes: make(map[string]ast.DataType),
+	}
+
+	selectStatement := query.Statements["select"].(*ast.SelectStatement)
+	fieldsTypes := SelectedFieldsTypes(selectStatement, scope)
+	for index, fieldName := range selectStatement.FieldsNames {
+		if aliasName, ok := selectStatement.AliasTable[fieldName]; ok {
+			fieldName = aliasName
+		}
+		table.FieldsNames = append(table.FieldsNames, fieldName)
+		table.FieldsTypes[fieldName] = fieldsTypes[index]
+	}
+
+	return table
+}
+
+func DefineDerivedTableFields(table *ast.DerivedTable, env *ast.Environment) {
+	for _, fieldName := range table.FieldsNames {
+		env.Define(fieldName, table.FieldsTypes[fieldName])
+	}
+}
+
+// ParseSubquery parses a select query between `(` and `)` in a new scope
+// nolint:lll
+func ParseSubquery(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (*ast.GQLQuery, *ast.Environment, Diagnostic) {
+	// Consume `(`
+	*position += 1
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != Select {
+		return nil, nil, *NewError("Expect `SELECT` statement after `(`").AddNote("Subqueries must be a `SELECT` statement between `(` and `)`").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	scope := env.NewScope()
+	query, err := ParseSelectQueryWithContext(context.NewSubqueryContext(), scope, tokens, position)
+	if err.Message != "" {
+		return nil, nil, err
+	}
+
+	if _, ok := query.Select.Statements["select"]; !ok {
+		return nil, nil, *NewError("Expect `SELECT` statement in subquery").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != RightParen {
+		return nil, nil, *NewError("Expect `)` to end subquery").AddHelp("Try to add ')' at the end of subquery").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume `)`
+	*position += 1
+
+	return query.Select, scope, Diagnostic{}
+}
+
+// ParseSubqueryExpression parses a subquery selecting one value, used as a scalar value or as `IN` values
+// nolint:lll
+func ParseSubqueryExpression(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (*ast.SubqueryExpression, Diagnostic) {
+	location := GetSafeLocation(tokens, *position)
+
+	query, scope, err := ParseSubquery(context, env, tokens, position)
+	if err.Message != "" {
+		return nil, err
+	}
+
+	selectStatement := query.Statements["select"].(*ast.SelectStatement)
+	if len(selectStatement.FieldsNames) != 1 {
+		return nil, *NewError(fmt.Sprintf("Subquery must select one value but got `%d`", len(selectStatement.FieldsNames))).AddHelp("Try to select only one value in the subquery").WithLocation(location)
+	}
+
+	valueType, _ := scope.ResolveType(selectStatement.FieldsNames[0])
+	subquery := &ast.SubqueryExpression{
+		Query:     query,
+		Env:       scope,
+		ValueType: valueType,
+	}
+
+	context.Subqueries = append(context.Subqueries, subquery)
+
+	return subquery, Diagnostic{}
+}
+
+// IsJoinKeyword reports if the tokens at the position start a `JOIN` clause
+func IsJoinKeyword(tokens *[]Token, position int) bool {
+	switch (*tokens)[position].Kind {
+	case Join, Inner:
+		return true
+	case Symbol:
+		if !strings.EqualFold((*tokens)[position].Literal, "left") || position+1 >= len(*tokens) {
+			return false
+		}
+		next := (*tokens)[position+1].Kind
+		return next == Join || next == Outer
+	default:
+		return false
+	}
+}
+
+// nolint:lll
+func ParseJoin(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (ast.Join, Diagnostic) {
+	joinKind := ast.InnerJoin
+	switch (*tokens)[*position].Kind {
+	case Inner:
+		// Consume `inner` keyword
 		*position += 1
-
-		tableNameToken, err := ConsumeKind(*tokens, *position, Symbol)
-		if err != nil {
-			return nil, *NewError("Expect `identifier` as a table name").AddNote("Table name must be an identifier").WithLocation(GetSafeLocation(tokens, *position))
+	case Symbol:
+		// Consume `left` keyword and the optional `outer` keyword
+		joinKind = ast.LeftJoin
+		*position += 1
+		if *position < len(*tokens) && (*tokens)[*position].Kind == Outer {
+			*position += 1
 		}
+	}
 
-		// Consume table name
-		*position += 1
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != Join {
+		return ast.Join{}, *NewError("Expect `JOIN` keyword").AddHelp("Try to use `INNER JOIN` or `LEFT JOIN`").WithLocation(GetSafeLocation(tokens, *position))
+	}
 
-		tableName = tableNameToken.Literal
+	// Consume `join` keyword
+	*position += 1
 
-		if _, ok := ast.TablesFieldsNames[tableName]; !ok {
-			return nil, *NewError("Unresolved table name").AddHelp("Check the documentations to see available tables").WithLocation(GetSafeLocation(tokens, *position))
-		}
+	tableName, tableAlias, err := ParseTableReference(context, env, tokens, position)
+	if err.Message != "" {
+		return ast.Join{}, err
+	}
 
-		RegisterCurrentTableFieldsTypes(tableName, env)
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != On {
+		return ast.Join{}, *NewError("Expect `ON` keyword after joined table").AddHelp("Try to add `ON` with a join condition like `ON a.commit_id = b.commit_id`").WithLocation(GetSafeLocation(tokens, *position))
 	}
 
-	// Make sure `SELECT *` used with specific table
-	if isSelectAll && tableName == "" {
-		return nil, *NewError("Expect `FROM` and table name after `SELECT *`").AddNote("Select all must be used with valid table name").WithLocation(GetSafeLocation(tokens, *position))
+	// Consume `on` keyword
+	*position += 1
+
+	if *position >= len(*tokens) {
+		return ast.Join{}, *NewError("Expect expression after `ON` keyword").AddHelp("Try to add boolean expression after `ON` keyword").WithLocation(GetSafeLocation(tokens, *position-1))
 	}
 
-	// Select input validations
-	if !isSelectAll && len(fieldsNames) == 0 {
-		return nil, *NewError("Incomplete input for select statement").AddHelp("Try select one or more values in the `SELECT` statement").AddNote("Select statements requires at least selecting one value").WithLocation(GetSafeLocation(tokens, *position-1))
+	aggregationsCountBefore := len(context.Aggregations)
+	windowsCountBefore := len(context.Windows)
+
+	conditionLocation := (*tokens)[*position].Location
+	condition, err := ParseExpression(context, env, tokens, position)
+	if err.Message != "" {
+		return ast.Join{}, err
 	}
 
-	// If it `select *` make all table fields selectable
-	if isSelectAll {
-		SelectAllTableFields(
-			tableName,
-			&context.SelectedFields,
-			&fieldsNames,
-			&fieldsValues,
-		)
+	conditionType := condition.ExprType(env)
+	if conditionType.Fmt() != "Boolean" {
+		return ast.Join{}, *NewError(fmt.Sprintf("Expect `ON` condition to be type %s but got %s", "Boolean", conditionType.Fmt())).AddNote("`JOIN` condition must be Boolean").WithLocation(conditionLocation)
 	}
 
-	// Type check all selected fields has type registered in type table
-	err := TypeCheckSelectedFields(env, tableName, &fieldsNames, tokens, *position)
-	if err.Message != "" {
-		return nil, err
+	if aggregationsCountBefore != len(context.Aggregations) {
+		return ast.Join{}, *NewError("Can't use Aggregation functions in `ON` condition").AddNote("Aggregation functions must be used after `GROUP BY` statement").WithLocation(conditionLocation)
 	}
 
-	return &ast.SelectStatement{
-		TableName:    tableName,
-		FieldsNames:  fieldsNames,
-		FieldsValues: fieldsValues,
-		AliasTable:   aliasTable,
-		IsDistinct:   isDistinct,
-	}, Diagnostic{}
+	if windowsCountBefore != len(context.Windows) {
+		return ast.Join{}, *NewError("Can't use Window functions in `ON` condition").AddNote("Window functions are evaluated after the tables are joined").WithLocation(conditionLocation)
+	}
+
+	join := ast.Join{
+		Kind:       joinKind,
+		TableName:  tableName,
+		TableAlias: tableAlias,
+		Condition:  condition,
+	}
+	join.DerivedTable = context.DerivedTables[join.TableReference()]
+
+	return join, Diagnostic{}
 }
 
 // nolint:goconst,lll
@@ -353,9 +1215,18 @@ func ParseWhereStatement(context *ParserContext, env *ast.Environment, tokens *[
 	}
 
 	aggregationsCountBefore := len(context.Aggregations)
+	windowsCountBefore := len(context.Windows)
 
 	conditionLocation := (*tokens)[*position].Location
-	condition, _ := ParseExpression(context, env, tokens, position)
+	condition, err := ParseExpression(context, env, tokens, position)
+	if err.Message != "" {
+		return nil, err
+	}
+
+	if windowsCountBefore != len(context.Windows) {
+		return nil, *NewError("Can't use Window functions in `WHERE` statement").AddNote("Window functions are evaluated after `WHERE`, `GROUP BY` and `HAVING` statements").WithLocation(conditionLocation)
+	}
+
 	conditionType := condition.ExprType(env)
 	if conditionType.Fmt() != "Boolean" {
 		return nil, *NewError(fmt.Sprintf("Expect `WHERE` condition to be type %s but got %s", "Boolean", conditionType)).AddNote("`WHERE` statement condition must be Boolean").WithLocation(conditionLocation)
@@ -377,19 +1248,45 @@ func ParseGroupByStatement(context *ParserContext, env *ast.Environment, tokens
 	}
 
 	*position += 1
-	if *position >= len(*tokens) || (*tokens)[*position].Kind != Symbol {
+	if *position >= len(*tokens) {
 		return nil, *NewError("Expect field name after `group by`").WithLocation(GetSafeLocation(tokens, *position-1))
 	}
 
-	fieldName := (*tokens)[*position].Literal
-	*position += 1
+	var values []ast.Expression
+	for {
+		location := GetSafeLocation(tokens, *position)
+		aggregationsCountBefore := len(context.Aggregations)
+		windowsCountBefore := len(context.Windows)
+
+		value, err := ParseExpression(context, env, tokens, position)
+		if err.Message != "" {
+			return nil, err
+		}
+
+		if aggregationsCountBefore != len(context.Aggregations) {
+			return nil, *NewError("Can't use Aggregation functions in `GROUP BY` statement").AddNote("Aggregation functions are evaluated for each group").WithLocation(location)
+		}
+
+		if windowsCountBefore != len(context.Windows) {
+			return nil, *NewError("Can't use Window functions in `GROUP BY` statement").AddNote("Window functions are evaluated after `WHERE`, `GROUP BY` and `HAVING` statements").WithLocation(location)
+		}
+
+		if symbol, ok := value.(*ast.SymbolExpression); ok && !env.Contains(symbol.Value) {
+			return nil, *NewError("Current table not contains field with this name").AddHelp("Check the documentations to see available fields for each tables").WithLocation(location)
+		}
+
+		values = append(values, value)
 
-	if !env.Contains(fieldName) {
-		return nil, *NewError("Current table not contains field with this name").AddHelp("Check the documentations to see available fields for each tables").WithLocation(GetSafeLocation(tokens, *position-1))
+		if *position < len(*tokens) && (*tokens)[*position].Kind == Comma {
+			// Consume `,`
+			*position += 1
+		} else {
+			break
+		}
 	}
 
 	context.HasGroupByStatement = true
-	return &ast.GroupByStatement{FieldName: fieldName}, Diagnostic{}
+	return &ast.GroupByStatement{Values: values}, Diagnostic{}
 }
 
 // nolint:lll
@@ -399,8 +1296,14 @@ func ParseHavingStatement(context *ParserContext, env *ast.Environment, tokens *
 		return nil, *NewError("Expect expression after `HAVING` keyword").AddHelp("Try to add boolean expression after `HAVING` keyword").AddNote("`HAVING` statement expects expression as condition").WithLocation(GetSafeLocation(tokens, *position-1))
 	}
 
+	windowsCountBefore := len(context.Windows)
+
 	conditionLocation := (*tokens)[*position].Location
 	condition, _ := ParseExpression(context, env, tokens, position)
+	if windowsCountBefore != len(context.Windows) {
+		return nil, *NewError("Can't use Window functions in `HAVING` statement").AddNote("Window functions are evaluated after `WHERE`, `GROUP BY` and `HAVING` statements").WithLocation(conditionLocation)
+	}
+
 	conditionType := condition.ExprType(env)
 	if conditionType.Fmt() != "Boolean" {
 		return nil, *NewError(fmt.Sprintf("Expect `HAVING` condition to be type %s but got %s", "Boolean", conditionType)).AddNote("`HAVING` statement condition must be Boolean").WithLocation(conditionLocation)
@@ -494,18 +1397,38 @@ func ParseOrderByStatement(context *ParserContext, env *ast.Environment, tokens
 
 func ParseExpression(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (ast.Expression, Diagnostic) {
 	aggregationsCountBefore := len(context.Aggregations)
+	windowsCountBefore := len(context.Windows)
 	expression, err := ParseAssignmentExpression(context, env, tokens, position)
 	if err.Message != "" {
 		return nil, err
 	}
+
+	// Expressions using window functions are evaluated after the window functions, the aggregations used by them are
+	// already evaluated at this stage
+	if len(context.Windows) != windowsCountBefore {
+		if _, ok := expression.(*ast.SymbolExpression); ok {
+			return expression, Diagnostic{}
+		}
+
+		columnName := context.GenerateColumnName()
+		env.Define(columnName, expression.ExprType(env))
+		context.HiddenSelections = append(context.HiddenSelections, columnName)
+		context.Windows[columnName] = ast.WindowValue{Expression: expression}
+
+		return &ast.SymbolExpression{
+			Value: columnName,
+		}, Diagnostic{}
+	}
+
 	hasAggregations := len(context.Aggregations) != aggregationsCountBefore
 
 	if hasAggregations {
 		columnName := context.GenerateColumnName()
 		env.Define(columnName, expression.ExprType(env))
 
-		// Register the new aggregation generated field if the this expression is after group by
-		if context.HasGroupByStatement && !contains(context.HiddenSelections, columnName) {
+		// Register the new aggregation generated field, it's not a selected field if this expression is after group by or
+		// inside a window function call
+		if !contains(context.HiddenSelections, columnName) {
 			context.HiddenSelections = append(context.HiddenSelections, columnName)
 		}
 
@@ -607,6 +1530,24 @@ func ParseInExpression(context *ParserContext, env *ast.Environment, tokens *[]T
 			return nil, *NewError("Expects values between `(` and `)` after `IN` keyword").WithLocation(inLocation)
 		}
 
+		if *position+1 < len(*tokens) && (*tokens)[*position+1].Kind == Select {
+			subquery, err := ParseSubqueryExpression(context, env, tokens, position)
+			if err.Message != "" {
+				return nil, err
+			}
+
+			if !subquery.ValueType.IsAny() && !expression.ExprType(env).IsAny() && !subquery.ValueType.Equal(expression.ExprType(env)) {
+				return nil, *NewError("Argument and Values of In Expression must have the same type").WithLocation(inLocation)
+			}
+
+			return &ast.InExpression{
+				Argument:      expression,
+				Subquery:      subquery,
+				ValuesType:    subquery.ValueType,
+				HasNotKeyword: hasNotKeyword,
+			}, Diagnostic{}
+		}
+
 		values, err := ParseArgumentsExpressions(context, env, tokens, position)
 		if err.Message != "" {
 			return nil, err
@@ -1297,13 +2238,23 @@ func ParseFunctionCallExpression(context *ParserContext, env *ast.Environment, t
 			}, Diagnostic{}
 		}
 
+		// Check if this function is a Window function, aggregation functions are window functions if `OVER` is used
+		_, isAggregation := ast.Aggregations[functionName]
+		if _, ok := ast.WindowFunctions[functionName]; ok && (!isAggregation || IsWindowFunctionCall(tokens, *position)) {
+			return ParseWindowFunctionCall(context, env, functionName, tokens, position)
+		}
+
 		// Check if this function is an Aggregation functions
-		if _, ok := ast.Aggregations[functionName]; ok {
+		if isAggregation {
 			arguments, err := ParseArgumentsExpressions(context, env, tokens, position)
 			if err.Message != "" {
 				return nil, err
 			}
 
+			if *position < len(*tokens) && (*tokens)[*position].Kind == Over {
+				return nil, *NewError(fmt.Sprintf("Aggregation function `%s` can't be used as Window function", functionName)).AddNote("Only `SUM` and `COUNT` aggregation functions can be used with `OVER` clause").WithLocation(functionNameLocation)
+			}
+
 			prototype := ast.AggregationsProtos[functionName]
 			parameters := []ast.DataType{prototype.Parameter}
 			returnType := prototype.Result
@@ -1320,7 +2271,7 @@ func ParseFunctionCallExpression(context *ParserContext, env *ast.Environment, t
 			}
 
 			argumentResult, argueErr := GetExpressionName(arguments[0])
-			if argueErr.Error() != "" {
+			if argueErr != nil {
 				return nil, *NewError("Invalid Aggregation function argument").AddHelp("Try to use field name as Aggregation function argument").AddNote("Aggregation function accept field name as argument").WithLocation(functionNameLocation)
 			}
 
@@ -1353,6 +2304,137 @@ func ParseFunctionCallExpression(context *ParserContext, env *ast.Environment, t
 	return expression, Diagnostic{}
 }
 
+// IsWindowFunctionCall returns true if the function call arguments starting at the position are followed by `OVER`
+func IsWindowFunctionCall(tokens *[]Token, position int) bool {
+	depth := 0
+	for ; position < len(*tokens); position++ {
+		switch (*tokens)[position].Kind {
+		case LeftParen:
+			depth++
+		case RightParen:
+			depth--
+			if depth == 0 {
+				return position+1 < len(*tokens) && (*tokens)[position+1].Kind == Over
+			}
+		}
+	}
+	return false
+}
+
+// ParseWindowFunctionCall parses the window function arguments and its `OVER` clause, the result is registered as a
+// generated column which is computed after the rows are grouped
+// nolint:lll
+func ParseWindowFunctionCall(context *ParserContext, env *ast.Environment, functionName string, tokens *[]Token, position *int) (ast.Expression, Diagnostic) {
+	functionNameLocation := GetSafeLocation(tokens, *position)
+	windowsCountBefore := len(context.Windows)
+
+	arguments, err := ParseArgumentsExpressions(context, env, tokens, position)
+	if err.Message != "" {
+		return nil, err
+	}
+
+	prototype := ast.WindowFunctionsProtos[functionName]
+	err = CheckWindowFunctionArguments(env, &arguments, prototype.Parameters, functionName, functionNameLocation)
+	if err.Message != "" {
+		return nil, err
+	}
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != Over {
+		return nil, *NewError(fmt.Sprintf("Expect `OVER` clause after Window function `%s`", functionName)).AddHelp("Try to add `OVER ()` after the function call").WithLocation(functionNameLocation)
+	}
+
+	window, err := ParseWindow(context, env, tokens, position)
+	if err.Message != "" {
+		return nil, err
+	}
+
+	if len(context.Windows) != windowsCountBefore {
+		return nil, *NewError("Can't use Window functions inside Window function call").AddNote("Window function arguments and `OVER` clause are evaluated before the Window functions").WithLocation(functionNameLocation)
+	}
+
+	returnType := prototype.Result
+	if returnType == nil {
+		returnType = arguments[0].ExprType(env)
+	}
+
+	columnName := context.GenerateColumnName()
+	context.HiddenSelections = append(context.HiddenSelections, columnName)
+
+	// Register window function generated name with return type
+	env.Define(columnName, returnType)
+
+	context.Windows[columnName] = ast.WindowValue{
+		Function: &ast.WindowFunctionCall{
+			Name:      functionName,
+			Arguments: arguments,
+			Window:    window,
+		},
+	}
+
+	return &ast.SymbolExpression{
+		Value: columnName,
+	}, Diagnostic{}
+}
+
+// ParseWindow parses `OVER ([PARTITION BY expressions] [ORDER BY arguments])`
+// nolint:lll
+func ParseWindow(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) (ast.Window, Diagnostic) {
+	// Consume `OVER` keyword
+	*position += 1
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != LeftParen {
+		return ast.Window{}, *NewError("Expect `(` after `OVER` keyword").AddHelp("Try to use `OVER ()` or `OVER (PARTITION BY ... ORDER BY ...)`").WithLocation(GetSafeLocation(tokens, *position-1))
+	}
+
+	// Consume `(`
+	*position += 1
+
+	var window ast.Window
+	if *position < len(*tokens) && (*tokens)[*position].Kind == Partition {
+		// Consume `PARTITION` keyword
+		*position += 1
+
+		if *position >= len(*tokens) || (*tokens)[*position].Kind != By {
+			return ast.Window{}, *NewError("Expect keyword `BY` after keyword `PARTITION`").AddHelp("Try to use `BY` keyword after `PARTITION`").WithLocation(GetSafeLocation(tokens, *position-1))
+		}
+
+		// Consume `BY` keyword
+		*position += 1
+
+		for {
+			value, err := ParseExpression(context, env, tokens, position)
+			if err.Message != "" {
+				return ast.Window{}, err
+			}
+			window.PartitionBy = append(window.PartitionBy, value)
+
+			if *position < len(*tokens) && (*tokens)[*position].Kind == Comma {
+				// Consume `,`
+				*position += 1
+			} else {
+				break
+			}
+		}
+	}
+
+	if *position < len(*tokens) && (*tokens)[*position].Kind == Order {
+		statement, err := ParseOrderByStatement(context, env, tokens, position)
+		if err.Message != "" {
+			return ast.Window{}, err
+		}
+		window.OrderBy = statement.(*ast.OrderByStatement)
+	}
+
+	if *position >= len(*tokens) || (*tokens)[*position].Kind != RightParen {
+		return ast.Window{}, *NewError("Expect `)` at the end of `OVER` clause").AddHelp("`OVER` clause can only contain `PARTITION BY` and `ORDER BY`").WithLocation(GetSafeLocation(tokens, *position))
+	}
+
+	// Consume `)`
+	*position += 1
+
+	return window, Diagnostic{}
+}
+
 // nolint:lll
 func ParseArgumentsExpressions(context *ParserContext, env *ast.Environment, tokens *[]Token, position *int) ([]ast.Expression, Diagnostic) {
 	var arguments []ast.Expression
@@ -1366,7 +2448,7 @@ func ParseArgumentsExpressions(context *ParserContext, env *ast.Environment, tok
 			}
 
 			argumentLiteral, erral := GetExpressionName(argument)
-			if erral == nil {
+			if erral == nil && !contains(context.HiddenSelections, argumentLiteral) {
 				literal := argumentLiteral
 				context.HiddenSelections = append(context.HiddenSelections, literal)
 			}
@@ -1403,8 +2485,28 @@ func ParsePrimaryExpression(context *ParserContext, env *ast.Environment, tokens
 		}, Diagnostic{}
 	case Symbol:
 		value := (*tokens)[*position].Literal
+		location := (*tokens)[*position].Location
 		*position += 1
-		if !contains(context.SelectedFields, value) {
+		// Function names are not fields
+		isFunctionName := *position < len(*tokens) && (*tokens)[*position].Kind == LeftParen
+		if !isFunctionName {
+			// Table qualified field name like `commits.title`
+			tableReference := ""
+			if *position+1 < len(*tokens) && (*tokens)[*position].Kind == Dot && (*tokens)[*position+1].Kind == Symbol {
+				tableReference = value
+				value = (*tokens)[*position+1].Literal
+				location.End = (*tokens)[*position+1].Location.End
+				// Consume `.` and the field name
+				*position += 2
+			}
+
+			fieldName, err := ResolveFieldName(context, env, tableReference, value, location)
+			if err.Message != "" {
+				return nil, err
+			}
+			value = fieldName
+		}
+		if !isFunctionName && !contains(context.SelectedFields, value) && !contains(context.HiddenSelections, value) {
 			context.HiddenSelections = append(context.HiddenSelections, value)
 		}
 		return &ast.SymbolExpression{Value: value}, Diagnostic{}
@@ -1436,6 +2538,14 @@ func ParsePrimaryExpression(context *ParserContext, env *ast.Environment, tokens
 		*position += 1
 		return &ast.NullExpression{}, Diagnostic{}
 	case LeftParen:
+		if *position+1 < len(*tokens) && (*tokens)[*position+1].Kind == Select {
+			subquery, err := ParseSubqueryExpression(context, env, tokens, position)
+			if err.Message != "" {
+				return nil, err
+			}
+			subquery.IsScalar = true
+			return subquery, Diagnostic{}
+		}
 		return ParseGroupExpression(context, env, tokens, position)
 	case Case:
 		return ParseCaseExpression(context, env, tokens, position)
@@ -1643,6 +2753,55 @@ func CheckFunctionCallArguments(env *ast.Environment, arguments *[]ast.Expressio
 	return nil, Diagnostic{}
 }
 
+// CheckWindowFunctionArguments checks the arguments count and types, the window function parameters can end with more
+// than one optional parameter
+// nolint:lll
+func CheckWindowFunctionArguments(env *ast.Environment, arguments *[]ast.Expression, parameters []ast.DataType, functionName string, location Location) Diagnostic {
+	requiredCount := 0
+	for _, parameter := range parameters {
+		if _, ok := parameter.(ast.Optional); !ok {
+			requiredCount++
+		}
+	}
+
+	argumentsLen := len(*arguments)
+	if requiredCount == len(parameters) && argumentsLen != requiredCount {
+		return *NewError(fmt.Sprintf("Function `%s` expects `%d` arguments but got `%d`", functionName, requiredCount, argumentsLen)).WithLocation(location)
+	}
+
+	if argumentsLen < requiredCount {
+		return *NewError(fmt.Sprintf("Function `%s` expects at least `%d` arguments but got `%d`", functionName, requiredCount, argumentsLen)).WithLocation(location)
+	}
+
+	if argumentsLen > len(parameters) {
+		return *NewError(fmt.Sprintf("Function `%s` expects at most `%d` arguments but got `%d`", functionName, len(parameters), argumentsLen)).WithLocation(location)
+	}
+
+	for index := range *arguments {
+		parameterType := parameters[index]
+		if optional, ok := parameterType.(ast.Optional); ok {
+			parameterType = optional.DataType
+		}
+
+		argument := (*arguments)[index]
+		switch result := IsExpressionTypeEquals(env, argument, parameterType).(type) {
+		case Equals:
+			// do nothing
+		case RightSideCasted:
+			(*arguments)[index] = result.expr
+		case LeftSideCasted:
+			(*arguments)[index] = result.expr
+		case Error:
+			return *result.diag.WithLocation(location)
+		default:
+			argumentType := argument.ExprType(env)
+			return *NewError(fmt.Sprintf("Function `%s` argument number %d with type `%s` don't match expected type `%s`", functionName, index, argumentType.Fmt(), parameterType.Fmt())).WithLocation(location)
+		}
+	}
+
+	return Diagnostic{}
+}
+
 func TypeCheckSelectedFields(env *ast.Environment, tableName string, fieldNames *[]string, tokens *[]Token, position int) Diagnostic {
 	for _, fieldName := range *fieldNames {
 		if dataType, err := env.ResolveType(fieldName); err == nil {
@@ -1657,12 +2816,53 @@ func TypeCheckSelectedFields(env *ast.Environment, tableName string, fieldNames
 	return Diagnostic{}
 }
 
+// nolint:lll
+func TypeCheckMutatedField(env *ast.Environment, tableName, fieldName string, value *ast.Expression, location Location) Diagnostic {
+	fieldType := ast.TableFieldType(tableName, fieldName)
+
+	switch result := IsExpressionTypeEquals(env, *value, fieldType).(type) {
+	case Equals:
+		// do nothing
+	case RightSideCasted:
+		*value = result.expr
+	case LeftSideCasted:
+		*value = result.expr
+	case Error:
+		return *result.diag.WithLocation(location)
+	default:
+		return *NewError(fmt.Sprintf("Table `%s` field `%s` expects value with type `%s` but got `%s`", tableName, fieldName, fieldType.Fmt(), (*value).ExprType(env).Fmt())).WithLocation(location)
+	}
+
+	return Diagnostic{}
+}
+
+// nolint:lll
+func TypeCheckInsertSelect(env *ast.Environment, tableName string, fieldsNames []string, query *ast.GQLQuery, location Location) Diagnostic {
+	selectStatement, ok := query.Statements["select"].(*ast.SelectStatement)
+	if !ok {
+		return *NewError("Expect `SELECT` statement as insert rows source").WithLocation(location)
+	}
+
+	if len(selectStatement.FieldsValues) != len(fieldsNames) {
+		return *NewError(fmt.Sprintf("Expect `%d` selected values but got `%d`", len(fieldsNames), len(selectStatement.FieldsValues))).AddNote("The select statement must select one value for each field name").WithLocation(location)
+	}
+
+	for index := range selectStatement.FieldsValues {
+		err := TypeCheckMutatedField(env, tableName, fieldsNames[index], &selectStatement.FieldsValues[index], location)
+		if err.Message != "" {
+			return err
+		}
+	}
+
+	return Diagnostic{}
+}
+
 func UnExpectedStatementError(tokens *[]Token, position *int) Diagnostic {
 	token := (*tokens)[*position]
 	location := token.Location
 
 	if location.Start == 0 {
-		return *NewError("Unexpected statement").AddHelp("Expect query to start with `SELECT` or `SET` keyword").WithLocation(location)
+		return *NewError("Unexpected statement").AddHelp("Expect query to start with `SELECT`, `SET`, `INSERT`, `UPDATE` or `DELETE` keyword").WithLocation(location)
 	}
 
 	return *NewError("Unexpected statement").WithLocation(location)
@@ -1734,11 +2934,72 @@ func GetExpressionName(expression ast.Expression) (string, error) {
 func RegisterCurrentTableFieldsTypes(tableName string, symbolTable *ast.Environment) {
 	tableFieldsNames := ast.TablesFieldsNames[tableName]
 	for _, fieldName := range tableFieldsNames {
-		fieldType := ast.TablesFieldsTypes[fieldName]
+		fieldType := ast.TableFieldType(tableName, fieldName)
 		symbolTable.Define(fieldName, fieldType)
 	}
 }
 
+// ResolveFieldName returns the name of the field as stored in the selected rows, in join queries each table field is
+// qualified with the table name or alias
+// nolint:lll
+func ResolveFieldName(context *ParserContext, env *ast.Environment, tableReference, fieldName string, location Location) (string, Diagnostic) {
+	if tableReference != "" {
+		tableName, ok := context.TablesNames[tableReference]
+		if !ok {
+			return "", *NewError(fmt.Sprintf("Unresolved table name or alias `%s`", tableReference)).AddHelp("Qualified fields must use a table name or alias from the `FROM` statement").WithLocation(location)
+		}
+
+		if !contains(context.TableFieldsNames(tableReference), fieldName) {
+			return "", *NewError(fmt.Sprintf("Table %s has no field with name %s", tableName, fieldName)).WithLocation(location)
+		}
+
+		if !context.IsJoinQuery() {
+			return fieldName, Diagnostic{}
+		}
+
+		qualifiedName := tableReference + "." + fieldName
+		env.Define(qualifiedName, context.TableFieldType(tableReference, fieldName))
+		return qualifiedName, Diagnostic{}
+	}
+
+	if !context.IsJoinQuery() || contains(context.SelectedFields, fieldName) {
+		return fieldName, Diagnostic{}
+	}
+
+	var references []string
+	for _, reference := range context.TablesReferences {
+		if contains(context.TableFieldsNames(reference), fieldName) {
+			references = append(references, reference)
+		}
+	}
+
+	switch len(references) {
+	case 0:
+		return fieldName, Diagnostic{}
+	case 1:
+		qualifiedName := references[0] + "." + fieldName
+		env.Define(qualifiedName, context.TableFieldType(references[0], fieldName))
+		return qualifiedName, Diagnostic{}
+	default:
+		return "", *NewError(fmt.Sprintf("Field name `%s` is ambiguous", fieldName)).AddHelp(fmt.Sprintf("Qualify the field with one of %s", strings.Join(references, ", "))).WithLocation(location)
+	}
+}
+
+// SelectAllFromTablesFields selects the fields of the joined and derived tables in the `FROM` statement
+func SelectAllFromTablesFields(context *ParserContext, env *ast.Environment, fieldsNames *[]string, fieldsValues *[]ast.Expression) {
+	for _, reference := range context.TablesReferences {
+		for _, field := range context.TableFieldsNames(reference) {
+			fieldName, _ := ResolveFieldName(context, env, reference, field, Location{})
+			if contains(*fieldsNames, fieldName) {
+				continue
+			}
+			context.SelectedFields = append(context.SelectedFields, fieldName)
+			*fieldsNames = append(*fieldsNames, fieldName)
+			*fieldsValues = append(*fieldsValues, &ast.SymbolExpression{Value: fieldName})
+		}
+	}
+}
+
 func SelectAllTableFields(tableName string, selectedFields, fieldsNames *[]string, fieldsValues *[]ast.Expression) {
 	if tableFields, ok := ast.TablesFieldsNames[tableName]; ok {
 		for _, field := range tableFields {
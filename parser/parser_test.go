@@ -111,6 +111,185 @@ func TestParseSetQuery(t *testing.T) {
 	}
 }
 
+func TestParseInsertQuery(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: INSERT INTO refs (full_name, commit_id) VALUES ("refs/heads/main", "HEAD"), ("refs/tags/v1", "HEAD")
+	tokens, _ := Tokenize(`INSERT INTO refs (full_name, commit_id) VALUES ("refs/heads/main", "HEAD"), ("refs/tags/v1", "HEAD")`)
+	position := 0
+
+	query, err := ParseInsertQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "refs", query.Insert.TableName)
+	assert.Equal(t, []string{"full_name", "commit_id"}, query.Insert.FieldsNames)
+	assert.Equal(t, 2, len(query.Insert.Values))
+
+	// Test: INSERT INTO refs (full_name, commit_id) VALUES ("refs/heads/main")
+	tokens, _ = Tokenize(`INSERT INTO refs (full_name, commit_id) VALUES ("refs/heads/main")`)
+	position = 0
+
+	_, err = ParseInsertQuery(&env, &tokens, &position)
+	assert.Equal(t, "Expect `2` values but got `1`", err.Message)
+
+	// Test: INSERT INTO tags (name, commit_id, message) SELECT title, commit_id, message FROM commits
+	tokens, _ = Tokenize(`INSERT INTO tags (name, commit_id, message) SELECT title, commit_id, message FROM commits`)
+	position = 0
+
+	query, err = ParseInsertQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "tags", query.Insert.TableName)
+	assert.NotNil(t, query.Insert.Select)
+	assert.Equal(t, 0, len(query.Insert.Values))
+
+	// Test: INSERT INTO refs (name) VALUES ("main")
+	tokens, _ = Tokenize(`INSERT INTO refs (name) VALUES ("main")`)
+	position = 0
+
+	_, err = ParseInsertQuery(&env, &tokens, &position)
+	assert.Equal(t, "Table `refs` has no mutable field with name `name`", err.Message)
+
+	// Test: INSERT INTO notes (notes_ref, commit_id, note) VALUES ("ci", "HEAD", "Build: passed")
+	tokens, _ = Tokenize(`INSERT INTO notes (notes_ref, commit_id, note) VALUES ("ci", "HEAD", "Build: passed")`)
+	position = 0
+
+	query, err = ParseInsertQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "notes", query.Insert.TableName)
+	assert.Equal(t, []string{"notes_ref", "commit_id", "note"}, query.Insert.FieldsNames)
+}
+
+func TestParseInsertValues(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	context := NewParserContext()
+
+	// Test: ("refs/heads/main", "HEAD")
+	tokens, _ := Tokenize(`("refs/heads/main", "HEAD")`)
+	position := 0
+
+	values, err := ParseInsertValues(context, &env, "refs", []string{"full_name", "commit_id"}, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, 2, len(values))
+
+	// Test: ("refs/heads/main", 1)
+	tokens, _ = Tokenize(`("refs/heads/main", 1)`)
+	position = 0
+
+	_, err = ParseInsertValues(context, &env, "refs", []string{"full_name", "commit_id"}, &tokens, &position)
+	assert.Equal(t, "Table `refs` field `commit_id` expects value with type `Text` but got `Integer`", err.Message)
+}
+
+func TestTypeCheckInsertSelect(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: SELECT title, commit_id FROM commits
+	tokens, _ := Tokenize(`SELECT title, commit_id FROM commits`)
+	position := 0
+
+	query, _ := ParseSelectQuery(&env, &tokens, &position)
+	err := TypeCheckInsertSelect(&env, "tags", []string{"name", "commit_id"}, query.Select, Location{})
+	assert.Equal(t, "", err.Message)
+
+	err = TypeCheckInsertSelect(&env, "tags", []string{"name", "commit_id", "message"}, query.Select, Location{})
+	assert.Equal(t, "Expect `3` selected values but got `2`", err.Message)
+
+	// Test: SELECT datetime, commit_id FROM commits
+	tokens, _ = Tokenize(`SELECT datetime, commit_id FROM commits`)
+	position = 0
+
+	query, _ = ParseSelectQuery(&env, &tokens, &position)
+	err = TypeCheckInsertSelect(&env, "tags", []string{"name", "commit_id"}, query.Select, Location{})
+	assert.Equal(t, "Table `tags` field `name` expects value with type `Text` but got `DateTime`", err.Message)
+}
+
+func TestParseUpdateQuery(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: UPDATE refs SET commit_id = "HEAD" WHERE name = "main"
+	tokens, _ := Tokenize(`UPDATE refs SET commit_id = "HEAD" WHERE name = "main"`)
+	position := 0
+
+	query, err := ParseUpdateQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "refs", query.Update.TableName)
+	assert.Equal(t, []string{"commit_id"}, query.Update.FieldsNames)
+	assert.NotNil(t, query.Update.Where)
+
+	// Test: UPDATE refs SET commit_id
+	tokens, _ = Tokenize(`UPDATE refs SET commit_id`)
+	position = 0
+
+	_, err = ParseUpdateQuery(&env, &tokens, &position)
+	assert.Equal(t, "Expect `=` and value after field name", err.Message)
+}
+
+func TestParseDeleteQuery(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: DELETE FROM refs WHERE type = "tag"
+	tokens, _ := Tokenize(`DELETE FROM refs WHERE type = "tag"`)
+	position := 0
+
+	query, err := ParseDeleteQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "refs", query.Delete.TableName)
+	assert.NotNil(t, query.Delete.Where)
+
+	// Test: DELETE FROM branches WHERE is_remote = false AND commit_count < 2
+	tokens, _ = Tokenize(`DELETE FROM branches WHERE is_remote = false AND commit_count < 2`)
+	position = 0
+
+	query, err = ParseDeleteQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "branches", query.Delete.TableName)
+	assert.NotNil(t, query.Delete.Where)
+
+	// Test: DELETE refs
+	tokens, _ = Tokenize(`DELETE refs`)
+	position = 0
+
+	_, err = ParseDeleteQuery(&env, &tokens, &position)
+	assert.Equal(t, "Expect `FROM` keyword after `DELETE` keyword", err.Message)
+}
+
+func TestParseMutableTableName(t *testing.T) {
+	// Test: refs
+	tokens, _ := Tokenize("refs")
+	position := 0
+
+	tableName, err := ParseMutableTableName(&tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "refs", tableName)
+	assert.Equal(t, 1, position)
+
+	// Test: commits
+	tokens, _ = Tokenize("commits")
+	position = 0
+
+	_, err = ParseMutableTableName(&tokens, &position)
+	assert.Equal(t, "Table `commits` can't be mutated", err.Message)
+}
+
 //nolint:gocritic
 func TestParseSelectQuery(t *testing.T) {
 	env := ast.Environment{
@@ -185,6 +364,15 @@ func TestParseSelectQuery(t *testing.T) {
 	if err.Message != "" {
 		t.Errorf("ParserGql failed with error: %v", err)
 	}
+
+	// Test: SELECT line FROM blame WHERE path = "go.mod", the tables can use the `WHERE` condition
+	tokens, _ = Tokenize(`SELECT line FROM blame WHERE path = "go.mod"`)
+	position = 0
+
+	query, err := ParseSelectQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	selectStatement := query.Select.Statements["select"].(*ast.SelectStatement)
+	assert.Equal(t, query.Select.Statements["where"].(*ast.WhereStatement).Condition, selectStatement.Condition)
 }
 
 //nolint:gocritic
@@ -217,6 +405,539 @@ func TestParseSelectStatement(t *testing.T) {
 	}
 }
 
+func TestParseSelectStatementWithJoin(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: SELECT b.name, title FROM branches b INNER JOIN commits AS c ON b.name = c.commit_id
+	tokens, _ := Tokenize(`SELECT b.name, title FROM branches b INNER JOIN commits AS c ON b.name = c.commit_id`)
+	position := 0
+
+	statement, err := ParseSelectStatement(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, len(tokens), position)
+
+	selectStatement := statement.(*ast.SelectStatement)
+	assert.Equal(t, "branches", selectStatement.TableName)
+	assert.Equal(t, "b", selectStatement.TableAlias)
+	assert.Equal(t, []string{"b.name", "c.title"}, selectStatement.FieldsNames)
+	assert.Equal(t, 1, len(selectStatement.Joins))
+	assert.Equal(t, ast.InnerJoin, selectStatement.Joins[0].Kind)
+	assert.Equal(t, "c", selectStatement.Joins[0].TableReference())
+
+	// Test: SELECT * FROM tags LEFT JOIN refs ON tags.name = refs.name
+	tokens, _ = Tokenize(`SELECT * FROM tags LEFT JOIN refs ON tags.name = refs.name`)
+	position = 0
+
+	statement, err = ParseSelectStatement(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+
+	selectStatement = statement.(*ast.SelectStatement)
+	assert.Equal(t, []string{"tags.name", "tags.commit_id", "tags.tag_object_id", "tags.is_annotated", "tags.tagger_name", "tags.tagger_email", "tags.datetime", "tags.message", "tags.repo", "refs.name", "refs.full_name", "refs.type", "refs.repo"}, selectStatement.FieldsNames)
+	assert.Equal(t, ast.LeftJoin, selectStatement.Joins[0].Kind)
+
+	// Test: SELECT name FROM tags JOIN refs ON tags.name = refs.name
+	tokens, _ = Tokenize(`SELECT name FROM tags JOIN refs ON tags.name = refs.name`)
+	position = 0
+
+	_, err = ParseSelectStatement(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "Field name `name` is ambiguous", err.Message)
+}
+
+func TestFindFromKeyword(t *testing.T) {
+	// Test: SELECT title FROM commits
+	tokens, _ := Tokenize(`SELECT title FROM commits`)
+	assert.Equal(t, 2, FindFromKeyword(&tokens, 1))
+
+	// Test: SELECT (1 + 2) WHERE TRUE
+	tokens, _ = Tokenize(`SELECT (1 + 2) WHERE TRUE`)
+	assert.Equal(t, -1, FindFromKeyword(&tokens, 1))
+
+	// Test: SELECT "HEAD" UNION SELECT name FROM branches
+	tokens, _ = Tokenize(`SELECT "HEAD" UNION SELECT name FROM branches`)
+	assert.Equal(t, -1, FindFromKeyword(&tokens, 1))
+}
+
+func TestParseFromStatement(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: FROM refs r LEFT OUTER JOIN branches b ON r.full_name = b.name JOIN tags t ON r.name = t.name
+	tokens, _ := Tokenize(`FROM refs r LEFT OUTER JOIN branches b ON r.full_name = b.name JOIN tags t ON r.name = t.name`)
+	position := 0
+	context := NewParserContext()
+
+	tableName, tableAlias, joins, err := ParseFromStatement(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "refs", tableName)
+	assert.Equal(t, "r", tableAlias)
+	assert.Equal(t, 2, len(joins))
+	assert.Equal(t, ast.LeftJoin, joins[0].Kind)
+	assert.Equal(t, ast.InnerJoin, joins[1].Kind)
+	assert.Equal(t, []string{"r", "b", "t"}, context.TablesReferences)
+	assert.Equal(t, len(tokens), position)
+}
+
+func TestParseTableReference(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: commits LEFT JOIN
+	tokens, _ := Tokenize(`commits LEFT JOIN`)
+	position := 0
+	context := NewParserContext()
+
+	tableName, tableAlias, err := ParseTableReference(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "commits", tableName)
+	assert.Equal(t, "", tableAlias)
+	assert.Equal(t, 1, position)
+
+	// Test: commits
+	tokens, _ = Tokenize(`commits`)
+	position = 0
+
+	_, _, err = ParseTableReference(context, &env, &tokens, &position)
+	assert.Equal(t, "Table name or alias `commits` is used more than once", err.Message)
+
+	// Test: invalid AS
+	tokens, _ = Tokenize(`invalid AS`)
+	position = 0
+
+	_, _, err = ParseTableReference(context, &env, &tokens, &position)
+	assert.Equal(t, "Unresolved table name", err.Message)
+
+	// Test: tags AS
+	tokens, _ = Tokenize(`tags AS`)
+	position = 0
+
+	_, _, err = ParseTableReference(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `identifier` as table alias name", err.Message)
+}
+
+func TestParseWithQuery(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: WITH b AS (SELECT name, commit_count FROM branches), h AS (SELECT name AS n FROM b WHERE commit_count > 1) SELECT * FROM h
+	tokens, _ := Tokenize(`WITH b AS (SELECT name, commit_count FROM branches), h AS (SELECT name AS n FROM b WHERE commit_count > 1) SELECT * FROM h`)
+	position := 0
+
+	query, err := ParseWithQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, len(tokens), position)
+
+	selectStatement := query.Select.Statements["select"].(*ast.SelectStatement)
+	assert.Equal(t, "h", selectStatement.TableName)
+	assert.Equal(t, []string{"n"}, selectStatement.FieldsNames)
+	assert.Equal(t, true, selectStatement.DerivedTable.IsCommon)
+
+	// The second common table selects from the first one
+	innerStatement := selectStatement.DerivedTable.Query.Statements["select"].(*ast.SelectStatement)
+	assert.Equal(t, "b", innerStatement.TableName)
+	assert.Equal(t, true, innerStatement.DerivedTable.IsCommon)
+
+	// Test: WITH b AS (SELECT name FROM branches) SELECT x.name FROM b AS x JOIN b AS y ON x.name = y.name
+	tokens, _ = Tokenize(`WITH b AS (SELECT name FROM branches) SELECT x.name FROM b AS x JOIN b AS y ON x.name = y.name`)
+	position = 0
+
+	query, err = ParseWithQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+
+	selectStatement = query.Select.Statements["select"].(*ast.SelectStatement)
+	assert.Equal(t, selectStatement.DerivedTable, selectStatement.Joins[0].DerivedTable)
+
+	// Test: WITH b AS (SELECT name FROM branches) SELECT name FROM branches WHERE name IN (SELECT name FROM b)
+	tokens, _ = Tokenize(`WITH b AS (SELECT name FROM branches) SELECT name FROM branches WHERE name IN (SELECT name FROM b)`)
+	position = 0
+
+	query, err = ParseWithQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, 1, len(query.Select.Subqueries))
+
+	// Test: WITH b AS (SELECT name FROM branches)
+	tokens, _ = Tokenize(`WITH b AS (SELECT name FROM branches)`)
+	position = 0
+
+	_, err = ParseWithQuery(&env, &tokens, &position)
+	assert.Equal(t, "Expect `SELECT` query after common table expressions", err.Message)
+}
+
+func TestParseCommonTableExpression(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: b AS (SELECT name AS n, commit_count FROM branches)
+	tokens, _ := Tokenize(`b AS (SELECT name AS n, commit_count FROM branches)`)
+	position := 0
+	context := NewParserContext()
+
+	name, table, err := ParseCommonTableExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "b", name)
+	assert.Equal(t, len(tokens), position)
+	assert.Equal(t, true, table.IsCommon)
+	assert.Equal(t, []string{"n", "commit_count"}, table.FieldsNames)
+	assert.Equal(t, ast.Integer{}, table.FieldsTypes["commit_count"])
+
+	// Common tables fields are defined when the table is used
+	assert.Equal(t, false, env.Contains("n"))
+
+	// Test: b AS (SELECT name FROM branches) with b already defined
+	context.CommonTables["b"] = table
+	tokens, _ = Tokenize(`b AS (SELECT name FROM branches)`)
+	position = 0
+
+	_, _, err = ParseCommonTableExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Common table expression `b` is defined more than once", err.Message)
+
+	// Test: (SELECT name FROM branches)
+	tokens, _ = Tokenize(`(SELECT name FROM branches)`)
+	position = 0
+
+	_, _, err = ParseCommonTableExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `identifier` as common table expression name", err.Message)
+
+	// Test: t (SELECT name FROM branches)
+	tokens, _ = Tokenize(`t (SELECT name FROM branches)`)
+	position = 0
+
+	_, _, err = ParseCommonTableExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `AS` keyword after common table expression name", err.Message)
+
+	// Test: t AS SELECT name FROM branches
+	tokens, _ = Tokenize(`t AS SELECT name FROM branches`)
+	position = 0
+
+	_, _, err = ParseCommonTableExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `(` before common table expression query", err.Message)
+}
+
+func TestNewDerivedTable(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	tokens, _ := Tokenize(`SELECT name AS n, is_head FROM branches`)
+	position := 0
+	query, _ := ParseSelectQuery(&env, &tokens, &position)
+
+	table := NewDerivedTable(query.Select, &env)
+	assert.Equal(t, []string{"n", "is_head"}, table.FieldsNames)
+	assert.Equal(t, map[string]ast.DataType{"n": ast.Text{}, "is_head": ast.Boolean{}}, table.FieldsTypes)
+	assert.Equal(t, false, table.IsCommon)
+
+	scope := env.NewScope()
+	DefineDerivedTableFields(table, scope)
+	assert.Equal(t, true, scope.Contains("n"))
+	assert.Equal(t, true, scope.Contains("is_head"))
+}
+
+func TestParseSetOperation(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	selectStatement := &ast.SelectStatement{
+		TableName:   "branches",
+		FieldsNames: []string{"name", "commit_count"},
+		AliasTable:  map[string]string{},
+	}
+	RegisterCurrentTableFieldsTypes("branches", &env)
+
+	// Test: UNION ALL SELECT name, commit_count FROM branches UNION
+	tokens, _ := Tokenize(`UNION ALL SELECT name, commit_count FROM branches UNION`)
+	position := 0
+	context := NewParserContext()
+
+	operation, err := ParseSetOperation(context, &env, selectStatement, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, ast.SOUnion, operation.Operator)
+	assert.Equal(t, true, operation.IsAll)
+	assert.Equal(t, len(tokens)-1, position)
+	assert.Equal(t, "branches", operation.Query.Statements["select"].(*ast.SelectStatement).TableName)
+
+	// Test: EXCEPT SELECT name, 1
+	tokens, _ = Tokenize(`EXCEPT SELECT name, 1 FROM tags`)
+	position = 0
+
+	operation, err = ParseSetOperation(context, &env, selectStatement, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, ast.SOExcept, operation.Operator)
+	assert.Equal(t, false, operation.IsAll)
+
+	// Test: INTERSECT SELECT name FROM tags
+	tokens, _ = Tokenize(`INTERSECT SELECT name FROM tags`)
+	position = 0
+
+	_, err = ParseSetOperation(context, &env, selectStatement, &tokens, &position)
+	assert.Equal(t, "`INTERSECT` queries must select the same number of values but got `2` and `1`", err.Message)
+
+	// Test: UNION SELECT commit_count, name FROM branches
+	tokens, _ = Tokenize(`UNION SELECT commit_count, name FROM branches`)
+	position = 0
+
+	_, err = ParseSetOperation(context, &env, selectStatement, &tokens, &position)
+	assert.Equal(t, "`UNION` queries must select the same types but value `1` is Text and Integer", err.Message)
+
+	// Test: UNION ALL
+	tokens, _ = Tokenize(`UNION ALL`)
+	position = 0
+
+	_, err = ParseSetOperation(context, &env, selectStatement, &tokens, &position)
+	assert.Equal(t, "Expect `SELECT` query after `UNION`", err.Message)
+}
+
+func TestParseSelectQueryWithSetOperations(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: SELECT name FROM branches UNION SELECT name FROM tags EXCEPT SELECT "HEAD" ORDER BY name LIMIT 2
+	tokens, _ := Tokenize(`SELECT name FROM branches UNION SELECT name FROM tags EXCEPT SELECT "HEAD" ORDER BY name LIMIT 2`)
+	position := 0
+
+	query, err := ParseSelectQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, len(tokens), position)
+	assert.Equal(t, 2, len(query.Select.SetOperations))
+	assert.Equal(t, ast.SOUnion, query.Select.SetOperations[0].Operator)
+	assert.Equal(t, ast.SOExcept, query.Select.SetOperations[1].Operator)
+
+	// The statements after the last query are applied on the combined rows
+	assert.NotNil(t, query.Select.Statements["order"])
+	assert.NotNil(t, query.Select.Statements["limit"])
+	assert.Nil(t, query.Select.SetOperations[1].Query.Statements["order"])
+	assert.Nil(t, query.Select.SetOperations[1].Query.Statements["limit"])
+
+	// Test: SELECT name FROM branches ORDER BY name UNION SELECT name FROM tags
+	tokens, _ = Tokenize(`SELECT name FROM branches ORDER BY name UNION SELECT name FROM tags`)
+	position = 0
+
+	_, err = ParseSelectQuery(&env, &tokens, &position)
+	assert.Equal(t, "`ORDER BY`, `LIMIT` and `OFFSET` must be used after the last query of set operations", err.Message)
+}
+
+func TestSelectedFieldsTypes(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+	env.Define("n", ast.Text{})
+	env.Define("is_head", ast.Boolean{})
+
+	selectStatement := &ast.SelectStatement{
+		FieldsNames: []string{"name", "is_head", "missing"},
+		AliasTable:  map[string]string{"name": "n"},
+	}
+
+	assert.Equal(t, []ast.DataType{ast.Text{}, ast.Boolean{}, ast.Undefined{}}, SelectedFieldsTypes(selectStatement, &env))
+}
+
+func TestParseDerivedTable(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: (SELECT name AS n, commit_count FROM branches) AS b
+	tokens, _ := Tokenize(`(SELECT name AS n, commit_count FROM branches) AS b`)
+	position := 0
+	context := NewParserContext()
+
+	tableName, tableAlias, err := ParseDerivedTable(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "b", tableName)
+	assert.Equal(t, "b", tableAlias)
+	assert.Equal(t, len(tokens), position)
+	assert.Equal(t, []string{"n", "commit_count"}, context.DerivedTables["b"].FieldsNames)
+	assert.Equal(t, ast.Integer{}, context.TableFieldType("b", "commit_count"))
+
+	// Test: (SELECT name FROM branches)
+	tokens, _ = Tokenize(`(SELECT name FROM branches)`)
+	position = 0
+
+	_, _, err = ParseDerivedTable(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `identifier` as derived table alias name", err.Message)
+}
+
+func TestParseSubquery(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: (SELECT name AS n FROM branches WHERE is_head) LIMIT 1
+	tokens, _ := Tokenize(`(SELECT name AS n FROM branches WHERE is_head) LIMIT 1`)
+	position := 0
+
+	query, scope, err := ParseSubquery(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, 10, position)
+	assert.NotNil(t, query.Statements["where"])
+	assert.Nil(t, query.Statements["limit"])
+
+	// The subquery fields are defined in its own scope
+	assert.Equal(t, true, scope.Contains("n"))
+	assert.Equal(t, false, env.Contains("n"))
+
+	// Test: (1)
+	tokens, _ = Tokenize(`(1)`)
+	position = 0
+
+	_, _, err = ParseSubquery(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "Expect `SELECT` statement after `(`", err.Message)
+
+	// Test: (SELECT name FROM branches
+	tokens, _ = Tokenize(`(SELECT name FROM branches`)
+	position = 0
+
+	_, _, err = ParseSubquery(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "Expect `)` to end subquery", err.Message)
+}
+
+func TestParseSubqueryExpression(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: (SELECT commit_count FROM branches WHERE is_head)
+	tokens, _ := Tokenize(`(SELECT commit_count FROM branches WHERE is_head)`)
+	position := 0
+	context := NewParserContext()
+
+	subquery, err := ParseSubqueryExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, ast.Integer{}, subquery.ValueType)
+	assert.Equal(t, []*ast.SubqueryExpression{subquery}, context.Subqueries)
+
+	// Test: (SELECT name, commit_count FROM branches)
+	tokens, _ = Tokenize(`(SELECT name, commit_count FROM branches)`)
+	position = 0
+
+	_, err = ParseSubqueryExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Subquery must select one value but got `2`", err.Message)
+}
+
+func TestParseSelectQueryWithSubqueries(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: SELECT title, (SELECT name FROM branches WHERE is_head) AS head FROM commits WHERE commit_id IN (SELECT commit_id FROM diffs WHERE insertions > 500)
+	tokens, _ := Tokenize(`SELECT title, (SELECT name FROM branches WHERE is_head) AS head FROM commits WHERE commit_id IN (SELECT commit_id FROM diffs WHERE insertions > 500)`)
+	position := 0
+
+	query, err := ParseSelectQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, len(tokens), position)
+	assert.Equal(t, 2, len(query.Select.Subqueries))
+	assert.Equal(t, true, query.Select.Subqueries[0].IsScalar)
+	assert.Equal(t, false, query.Select.Subqueries[1].IsScalar)
+
+	where := query.Select.Statements["where"].(*ast.WhereStatement)
+	assert.Equal(t, query.Select.Subqueries[1], where.Condition.(*ast.InExpression).Subquery)
+
+	// Test: SELECT title FROM commits WHERE commit_id IN (SELECT commit_count FROM branches)
+	tokens, _ = Tokenize(`SELECT title FROM commits WHERE commit_id IN (SELECT commit_count FROM branches)`)
+	position = 0
+
+	_, err = ParseSelectQuery(&env, &tokens, &position)
+	assert.Equal(t, "Argument and Values of In Expression must have the same type", err.Message)
+
+	// Test: SELECT * FROM (SELECT name AS n FROM branches) AS b WHERE n LIKE "%main"
+	tokens, _ = Tokenize(`SELECT * FROM (SELECT name AS n FROM branches) AS b WHERE n LIKE "%main"`)
+	position = 0
+
+	query, err = ParseSelectQuery(&env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+
+	selectStatement := query.Select.Statements["select"].(*ast.SelectStatement)
+	assert.Equal(t, []string{"n"}, selectStatement.FieldsNames)
+	assert.NotNil(t, selectStatement.DerivedTable)
+}
+
+func TestIsJoinKeyword(t *testing.T) {
+	tokens, _ := Tokenize(`JOIN INNER left JOIN LEFT OUTER left (`)
+	assert.Equal(t, true, IsJoinKeyword(&tokens, 0))
+	assert.Equal(t, true, IsJoinKeyword(&tokens, 1))
+	assert.Equal(t, true, IsJoinKeyword(&tokens, 2))
+	assert.Equal(t, true, IsJoinKeyword(&tokens, 4))
+	assert.Equal(t, false, IsJoinKeyword(&tokens, 6))
+	assert.Equal(t, false, IsJoinKeyword(&tokens, 7))
+}
+
+func TestParseJoin(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	context := NewParserContext()
+	context.RegisterTable("c", "commits")
+
+	// Test: INNER JOIN diffs d ON c.commit_id = d.commit_id
+	tokens, _ := Tokenize(`INNER JOIN diffs d ON c.commit_id = d.commit_id`)
+	position := 0
+
+	join, err := ParseJoin(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, ast.InnerJoin, join.Kind)
+	assert.Equal(t, "diffs", join.TableName)
+	assert.Equal(t, "d", join.TableAlias)
+	assert.Equal(t, []string{"c.commit_id", "d.commit_id"}, context.HiddenSelections)
+
+	// Test: LEFT JOIN tags
+	tokens, _ = Tokenize(`LEFT JOIN tags`)
+	position = 0
+
+	_, err = ParseJoin(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `ON` keyword after joined table", err.Message)
+
+	// Test: INNER refs
+	tokens, _ = Tokenize(`INNER refs`)
+	position = 0
+
+	_, err = ParseJoin(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `JOIN` keyword", err.Message)
+
+	// Test: JOIN branches b ON b.commit_count
+	tokens, _ = Tokenize(`JOIN branches b ON b.commit_count`)
+	position = 0
+
+	_, err = ParseJoin(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `ON` condition to be type Boolean but got Integer", err.Message)
+}
+
 //nolint:gocritic
 func TestParseWhereStatement(t *testing.T) {
 	env := ast.Environment{
@@ -245,6 +966,14 @@ func TestParseWhereStatement(t *testing.T) {
 	if err.Message == "" {
 		t.Errorf("ParserGql failed with error: %v", err)
 	}
+
+	// Test: WHERE row_number() OVER () = 1
+	RegisterCurrentTableFieldsTypes("commits", &env)
+	tokens, _ = Tokenize(`WHERE row_number() OVER () = 1`)
+	position = 0
+
+	_, err = ParseWhereStatement(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "Can't use Window functions in `WHERE` statement", err.Message)
 }
 
 //nolint:gocritic
@@ -292,6 +1021,39 @@ func TestParseGroupByStatement(t *testing.T) {
 	if err.Message != "" {
 		t.Errorf("ParserGql failed with error: %v", err)
 	}
+
+	// Test: GROUP BY name, dayname(datetime)
+	parserContext := NewParserContext()
+	RegisterCurrentTableFieldsTypes("commits", &env)
+	tokens, _ = Tokenize(`GROUP BY name, dayname(datetime)`)
+	position = 0
+
+	statement, err := ParseGroupByStatement(parserContext, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, len(tokens), position)
+	assert.Equal(t, true, parserContext.HasGroupByStatement)
+
+	values := statement.(*ast.GroupByStatement).Values
+	assert.Equal(t, 2, len(values))
+	assert.Equal(t, &ast.SymbolExpression{Value: "name"}, values[0])
+	assert.Equal(t, "dayname", values[1].(*ast.CallExpression).FunctionName)
+
+	// Not selected fields are selected as hidden fields
+	assert.Equal(t, []string{"name", "datetime"}, parserContext.HiddenSelections)
+
+	// Test: GROUP BY count(name)
+	tokens, _ = Tokenize(`GROUP BY count(name)`)
+	position = 0
+
+	_, err = ParseGroupByStatement(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "Can't use Aggregation functions in `GROUP BY` statement", err.Message)
+
+	// Test: GROUP BY missing
+	tokens, _ = Tokenize(`GROUP BY missing`)
+	position = 0
+
+	_, err = ParseGroupByStatement(NewParserContext(), &env, &tokens, &position)
+	assert.Equal(t, "Current table not contains field with this name", err.Message)
 }
 
 //nolint:gocritic
@@ -498,6 +1260,19 @@ func TestParseExpression(t *testing.T) {
 	if err.Message != "" {
 		t.Errorf("ParserGql failed with error: %v", err)
 	}
+
+	// Test: row_number() OVER () + count(name), the expression is evaluated after the window functions
+	RegisterCurrentTableFieldsTypes("commits", &env)
+	windowContext := NewParserContext()
+	tokens, _ = Tokenize(`row_number() OVER () + count(name)`)
+	position = 0
+
+	expression, err := ParseExpression(windowContext, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, &ast.SymbolExpression{Value: "column_3"}, expression)
+	assert.Equal(t, 1, len(windowContext.Aggregations))
+	assert.NotNil(t, windowContext.Windows["column_1"].Function)
+	assert.NotNil(t, windowContext.Windows["column_3"].Expression)
 }
 
 //nolint:gocritic
@@ -1534,6 +2309,136 @@ func TestParseFunctionCallExpression(t *testing.T) {
 	if err.Message != "" {
 		t.Errorf("ParserGql failed with error: %v", err)
 	}
+
+	// The function name is not a hidden selection and arguments are registered once
+	assert.Equal(t, []string{"name"}, context.HiddenSelections)
+
+	// Test: concat("v", name, name)
+	context = ParserContext{}
+	tokens, _ = Tokenize(`concat("v", name, name)`)
+	position = 0
+
+	_, err = ParseFunctionCallExpression(&context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, []string{"name"}, context.HiddenSelections)
+}
+
+func TestIsWindowFunctionCall(t *testing.T) {
+	tokens, _ := Tokenize(`sum(abs(insertions)) OVER ()`)
+	assert.Equal(t, true, IsWindowFunctionCall(&tokens, 1))
+
+	tokens, _ = Tokenize(`sum(abs(insertions)) + 1`)
+	assert.Equal(t, false, IsWindowFunctionCall(&tokens, 1))
+
+	tokens, _ = Tokenize(`sum(insertions`)
+	assert.Equal(t, false, IsWindowFunctionCall(&tokens, 1))
+}
+
+func TestParseWindowFunctionCall(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+	RegisterCurrentTableFieldsTypes("commits", &env)
+
+	// Test: lag(datetime, 2) OVER (PARTITION BY name ORDER BY datetime)
+	tokens, _ := Tokenize(`lag(datetime, 2) OVER (PARTITION BY name ORDER BY datetime)`)
+	position := 0
+	context := NewParserContext()
+
+	expression, err := ParseFunctionCallExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, len(tokens), position)
+	assert.Equal(t, &ast.SymbolExpression{Value: "column_1"}, expression)
+	assert.Equal(t, ast.DateTime{}, env.Scopes["column_1"])
+	assert.Equal(t, []string{"datetime", "name", "column_1"}, context.HiddenSelections)
+
+	window := context.Windows["column_1"]
+	assert.Equal(t, "lag", window.Function.Name)
+	assert.Equal(t, 2, len(window.Function.Arguments))
+	assert.Equal(t, []ast.Expression{&ast.SymbolExpression{Value: "name"}}, window.Function.Window.PartitionBy)
+	assert.Equal(t, []ast.SortingOrder{ast.Ascending}, window.Function.Window.OrderBy.SortingOrders)
+
+	// Test: count(name) OVER () is a window function and not an aggregation
+	tokens, _ = Tokenize(`count(name) OVER ()`)
+	position = 0
+	context = NewParserContext()
+
+	_, err = ParseFunctionCallExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, 0, len(context.Aggregations))
+	assert.Equal(t, ast.Integer{}, env.Scopes["column_1"])
+
+	// Test: rank()
+	tokens, _ = Tokenize(`rank()`)
+	position = 0
+
+	_, err = ParseFunctionCallExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `OVER` clause after Window function `rank`", err.Message)
+
+	// Test: max(name) OVER ()
+	tokens, _ = Tokenize(`max(name) OVER ()`)
+	position = 0
+
+	_, err = ParseFunctionCallExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Aggregation function `max` can't be used as Window function", err.Message)
+
+	// Test: first_value(row_number() OVER ()) OVER ()
+	tokens, _ = Tokenize(`first_value(row_number() OVER ()) OVER ()`)
+	position = 0
+
+	_, err = ParseFunctionCallExpression(context, &env, &tokens, &position)
+	assert.Equal(t, "Can't use Window functions inside Window function call", err.Message)
+}
+
+func TestParseWindow(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+	RegisterCurrentTableFieldsTypes("commits", &env)
+
+	// Test: OVER ()
+	tokens, _ := Tokenize(`OVER ()`)
+	position := 0
+	context := NewParserContext()
+
+	window, err := ParseWindow(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, ast.Window{}, window)
+	assert.Equal(t, len(tokens), position)
+
+	// Test: OVER (PARTITION BY name, repo ORDER BY datetime DESC)
+	tokens, _ = Tokenize(`OVER (PARTITION BY name, repo ORDER BY datetime DESC)`)
+	position = 0
+
+	window, err = ParseWindow(context, &env, &tokens, &position)
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, 2, len(window.PartitionBy))
+	assert.Equal(t, []ast.SortingOrder{ast.Descending}, window.OrderBy.SortingOrders)
+
+	// Test: OVER name
+	tokens, _ = Tokenize(`OVER name`)
+	position = 0
+
+	_, err = ParseWindow(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `(` after `OVER` keyword", err.Message)
+
+	// Test: OVER (PARTITION name)
+	tokens, _ = Tokenize(`OVER (PARTITION name)`)
+	position = 0
+
+	_, err = ParseWindow(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect keyword `BY` after keyword `PARTITION`", err.Message)
+
+	// Test: OVER (ORDER BY name LIMIT 1)
+	tokens, _ = Tokenize(`OVER (ORDER BY name LIMIT 1)`)
+	position = 0
+
+	_, err = ParseWindow(context, &env, &tokens, &position)
+	assert.Equal(t, "Expect `)` at the end of `OVER` clause", err.Message)
 }
 
 //nolint:gocritic
@@ -1828,6 +2733,36 @@ func TestCheckFunctionCallExpression(t *testing.T) {
 }
 
 //nolint:goconst,gocritic,gomnd
+func TestCheckWindowFunctionArguments(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+	RegisterCurrentTableFieldsTypes("commits", &env)
+	parameters := ast.WindowFunctionsProtos["lead"].Parameters
+
+	arguments := []ast.Expression{&ast.SymbolExpression{Value: "name"}}
+	err := CheckWindowFunctionArguments(&env, &arguments, parameters, "lead", Location{})
+	assert.Equal(t, "", err.Message)
+
+	arguments = []ast.Expression{&ast.SymbolExpression{Value: "name"}, &ast.NumberExpression{Value: ast.IntegerValue{Value: 2}}, &ast.StringExpression{Value: "none"}}
+	err = CheckWindowFunctionArguments(&env, &arguments, parameters, "lead", Location{})
+	assert.Equal(t, "", err.Message)
+
+	arguments = []ast.Expression{}
+	err = CheckWindowFunctionArguments(&env, &arguments, parameters, "lead", Location{})
+	assert.Equal(t, "Function `lead` expects at least `1` arguments but got `0`", err.Message)
+
+	arguments = []ast.Expression{&ast.SymbolExpression{Value: "name"}, &ast.SymbolExpression{Value: "name"}}
+	err = CheckWindowFunctionArguments(&env, &arguments, parameters, "lead", Location{})
+	assert.Equal(t, "Function `lead` argument number 1 with type `Text` don't match expected type `Integer`", err.Message)
+
+	arguments = []ast.Expression{&ast.SymbolExpression{Value: "name"}}
+	err = CheckWindowFunctionArguments(&env, &arguments, ast.WindowFunctionsProtos["rank"].Parameters, "rank", Location{})
+	assert.Equal(t, "Function `rank` expects `0` arguments but got `1`", err.Message)
+}
+
 func TestTypeCheckSelectedFields(t *testing.T) {
 	env := ast.Environment{
 		Globals:      map[string]ast.Value{},
@@ -1864,6 +2799,24 @@ func TestTypeCheckSelectedFields(t *testing.T) {
 	}
 }
 
+func TestTypeCheckMutatedField(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	// Test: commit_id = "HEAD"
+	var value ast.Expression = &ast.StringExpression{Value: "HEAD", ValueType: ast.StringValueText}
+	err := TypeCheckMutatedField(&env, "refs", "commit_id", &value, Location{})
+	assert.Equal(t, "", err.Message)
+
+	// Test: commit_id = true
+	value = &ast.BooleanExpression{IsTrue: true}
+	err = TypeCheckMutatedField(&env, "refs", "commit_id", &value, Location{})
+	assert.Equal(t, "Table `refs` field `commit_id` expects value with type `Text` but got `Boolean`", err.Message)
+}
+
 //nolint:gocritic
 func TestUnExpectedStatementError(t *testing.T) {
 	// Test: start == 0
@@ -1980,6 +2933,80 @@ func TestRegisterCurrentTableFieldsTypes(t *testing.T) {
 }
 
 //nolint:gocritic
+func TestResolveFieldName(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	context := NewParserContext()
+	context.RegisterTable("commits", "commits")
+
+	// Single table queries use the field name
+	name, err := ResolveFieldName(context, &env, "commits", "title", Location{})
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "title", name)
+
+	_, err = ResolveFieldName(context, &env, "c", "title", Location{})
+	assert.Equal(t, "Unresolved table name or alias `c`", err.Message)
+
+	_, err = ResolveFieldName(context, &env, "commits", "is_head", Location{})
+	assert.Equal(t, "Table commits has no field with name is_head", err.Message)
+
+	// Join queries qualify the field name with the table reference
+	context.RegisterTable("d", "diffs")
+
+	name, err = ResolveFieldName(context, &env, "d", "insertions", Location{})
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "d.insertions", name)
+	assert.Equal(t, ast.Integer{}, env.Scopes["d.insertions"])
+
+	name, err = ResolveFieldName(context, &env, "", "title", Location{})
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "commits.title", name)
+
+	name, err = ResolveFieldName(context, &env, "", "unknown", Location{})
+	assert.Equal(t, "", err.Message)
+	assert.Equal(t, "unknown", name)
+
+	_, err = ResolveFieldName(context, &env, "", "commit_id", Location{})
+	assert.Equal(t, "Field name `commit_id` is ambiguous", err.Message)
+}
+
+func TestSelectAllFromTablesFields(t *testing.T) {
+	env := ast.Environment{
+		Globals:      map[string]ast.Value{},
+		GlobalsTypes: map[string]ast.DataType{},
+		Scopes:       map[string]ast.DataType{},
+	}
+
+	context := NewParserContext()
+	context.RegisterTable("t", "tags")
+	context.RegisterTable("r", "refs")
+
+	var fieldsNames []string
+	var fieldsValues []ast.Expression
+
+	SelectAllFromTablesFields(context, &env, &fieldsNames, &fieldsValues)
+	assert.Equal(t, []string{"t.name", "t.commit_id", "t.tag_object_id", "t.is_annotated", "t.tagger_name", "t.tagger_email", "t.datetime", "t.message", "t.repo", "r.name", "r.full_name", "r.type", "r.repo"}, fieldsNames)
+	assert.Equal(t, 13, len(fieldsValues))
+	assert.Equal(t, ast.Text{}, env.Scopes["r.full_name"])
+
+	// Derived table fields are not qualified when it is the only table
+	context = NewParserContext()
+	context.RegisterDerivedTable("d", &ast.DerivedTable{
+		FieldsNames: []string{"id", "total"},
+		FieldsTypes: map[string]ast.DataType{"id": ast.Text{}, "total": ast.Integer{}},
+	})
+
+	fieldsNames = nil
+	fieldsValues = nil
+
+	SelectAllFromTablesFields(context, &env, &fieldsNames, &fieldsValues)
+	assert.Equal(t, []string{"id", "total"}, fieldsNames)
+}
+
 func TestSelectAllTableFields(t *testing.T) {
 	var selectedFields []string
 	var fieldsNames []string
@@ -1988,9 +3015,9 @@ func TestSelectAllTableFields(t *testing.T) {
 	tableName := "branches"
 	SelectAllTableFields(tableName, &selectedFields, &fieldsNames, &fieldsValues)
 
-	assert.Equal(t, len(selectedFields), 5)
-	assert.Equal(t, len(fieldsNames), 5)
-	assert.Equal(t, len(fieldsValues), 5)
+	assert.Equal(t, len(selectedFields), 8)
+	assert.Equal(t, len(fieldsNames), 8)
+	assert.Equal(t, len(fieldsValues), 8)
 }
 
 func TestConsumeKind(t *testing.T) {
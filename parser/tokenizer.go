@@ -10,7 +10,13 @@ type TokenKind int
 
 const (
 	Set TokenKind = iota
+	With
 	Select
+	Insert
+	Into
+	Values
+	Update
+	Delete
 	Distinct
 	From
 	Group
@@ -20,6 +26,16 @@ const (
 	Offset
 	Order
 	By
+	Join
+	Inner
+	Outer
+	On
+	Union
+	Intersect
+	Except
+	All
+	Over
+	Partition
 	In
 	Is
 	Not
@@ -741,8 +757,20 @@ func resolveSymbolKind(literal string) TokenKind {
 	// Reserved keywords
 	case "set":
 		return Set
+	case "with":
+		return With
 	case "select":
 		return Select
+	case "insert":
+		return Insert
+	case "into":
+		return Into
+	case "values":
+		return Values
+	case "update":
+		return Update
+	case "delete":
+		return Delete
 	case "distinct":
 		return Distinct
 	case "from":
@@ -761,6 +789,26 @@ func resolveSymbolKind(literal string) TokenKind {
 		return Order
 	case "by":
 		return By
+	case "join":
+		return Join
+	case "inner":
+		return Inner
+	case "outer":
+		return Outer
+	case "on":
+		return On
+	case "union":
+		return Union
+	case "intersect":
+		return Intersect
+	case "except":
+		return Except
+	case "all":
+		return All
+	case "over":
+		return Over
+	case "partition":
+		return Partition
 	case "case":
 		return Case
 	case "when":
@@ -592,6 +592,86 @@ func TestResolveSymbolKind(t *testing.T) {
 	kind := resolveSymbolKind(literal)
 	assert.Equal(t, Set, kind)
 
+	// Insert: INSERT
+	literal = "INSERT"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Insert, kind)
+
+	// Values: VALUES
+	literal = "VALUES"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Values, kind)
+
+	// Update: UPDATE
+	literal = "UPDATE"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Update, kind)
+
+	// Delete: DELETE
+	literal = "DELETE"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Delete, kind)
+
+	// With: WITH
+	literal = "WITH"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, With, kind)
+
+	// Join: JOIN
+	literal = "JOIN"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Join, kind)
+
+	// Inner: INNER
+	literal = "INNER"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Inner, kind)
+
+	// Outer: OUTER
+	literal = "OUTER"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Outer, kind)
+
+	// On: ON
+	literal = "ON"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, On, kind)
+
+	// Union: UNION
+	literal = "UNION"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Union, kind)
+
+	// Intersect: INTERSECT
+	literal = "INTERSECT"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Intersect, kind)
+
+	// Except: EXCEPT
+	literal = "EXCEPT"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Except, kind)
+
+	// All: ALL
+	literal = "ALL"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, All, kind)
+
+	// Over: OVER
+	literal = "OVER"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Over, kind)
+
+	// Partition: PARTITION
+	literal = "PARTITION"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Partition, kind)
+
+	// Symbol: LEFT is also a function name
+	literal = "LEFT"
+	kind = resolveSymbolKind(literal)
+	assert.Equal(t, Symbol, kind)
+
 	// Symbol: NAME
 	literal = "NAME"
 	kind = resolveSymbolKind(literal)
@@ -27,7 +27,8 @@ type LeftSideCasted struct {
 func IsExpressionTypeEquals(scope *ast.Environment, expr ast.Expression, dataType ast.DataType) TypeCheckResult {
 	exprType := expr.ExprType(scope)
 
-	if exprType == dataType {
+	// Variant parameters are equal to any of their types
+	if dataType.IsAny() || exprType.Equal(dataType) || (dataType.IsVariant() && dataType.Equal(exprType)) {
 		return Equals{}
 	}
 
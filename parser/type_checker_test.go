@@ -68,6 +68,31 @@ func TestIsExpressionTypeEquals(t *testing.T) {
 	result = IsExpressionTypeEquals(&scope, &expr, integer)
 	_, isTest = result.(NotEqualAndCantImplicitCast)
 	assert.Equal(t, true, isTest)
+
+	// Any parameter
+	result = IsExpressionTypeEquals(&scope, &expr, ast.Any{})
+	_, isTest = result.(Equals)
+	assert.Equal(t, true, isTest)
+
+	// Variant parameter
+	result = IsExpressionTypeEquals(&scope, &expr, ast.Variant{ast.Integer{}, ast.Text{}})
+	_, isTest = result.(Equals)
+	assert.Equal(t, true, isTest)
+
+	// Varargs parameter
+	result = IsExpressionTypeEquals(&scope, &expr, ast.Varargs{DataType: ast.Any{}})
+	_, isTest = result.(Equals)
+	assert.Equal(t, true, isTest)
+
+	// Variant parameter with a non text argument
+	number := ast.NumberExpression{Value: ast.IntegerValue{Value: 1}}
+	result = IsExpressionTypeEquals(&scope, &number, ast.Variant{ast.Float{}, ast.Integer{}})
+	_, isTest = result.(Equals)
+	assert.Equal(t, true, isTest)
+
+	result = IsExpressionTypeEquals(&scope, &number, ast.Variant{ast.Date{}, ast.DateTime{}})
+	_, isTest = result.(NotEqualAndCantImplicitCast)
+	assert.Equal(t, true, isTest)
 }
 
 // nolint:funlen